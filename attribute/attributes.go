@@ -1,8 +1,13 @@
+// Code generated by greact_gen attr from html spec 2026-10-19. DO NOT EDIT.
+
 package attribute
 
 // Attribute represents htm attributes
 type Attribute struct {
-	Name     string
+	Name string
+
+	// Global is true for attributes allowed on all elements.
+	Global   bool
 	Elements []string
 }
 
@@ -17,8 +22,8 @@ var Map = map[string]Attribute{
 		Elements: []string{"form"},
 	},
 	"accesskey": Attribute{
-		Name:     "accesskey",
-		Elements: []string{"Globalattribute"},
+		Name:   "accesskey",
+		Global: true,
 	},
 	"action": Attribute{
 		Name:     "action",
@@ -41,12 +46,12 @@ var Map = map[string]Attribute{
 		Elements: []string{"script"},
 	},
 	"autocapitalize": Attribute{
-		Name:     "autocapitalize",
-		Elements: []string{"Globalattribute"},
+		Name:   "autocapitalize",
+		Global: true,
 	},
 	"autocomplete": Attribute{
 		Name:     "autocomplete",
-		Elements: []string{"form", "input", " select", "textarea"},
+		Elements: []string{"form", "input", "select", "textarea"},
 	},
 	"autofocus": Attribute{
 		Name:     "autofocus",
@@ -62,7 +67,7 @@ var Map = map[string]Attribute{
 	},
 	"bgcolor": Attribute{
 		Name:     "bgcolor",
-		Elements: []string{"body", "col", "colgroup", "marquee", "table", "tbody", "tfoot", "td", "th", "tr"},
+		Elements: []string{"body", "col", "colgroup", "marquee", "table", "tbody", "td", "tfoot", "th", "tr"},
 	},
 	"border": Attribute{
 		Name:     "border",
@@ -93,8 +98,8 @@ var Map = map[string]Attribute{
 		Elements: []string{"blockquote", "del", "ins", "q"},
 	},
 	"class": Attribute{
-		Name:     "class",
-		Elements: []string{"Globalattribute"},
+		Name:   "class",
+		Global: true,
 	},
 	"code": Attribute{
 		Name:     "code",
@@ -121,12 +126,12 @@ var Map = map[string]Attribute{
 		Elements: []string{"meta"},
 	},
 	"contenteditable": Attribute{
-		Name:     "contenteditable",
-		Elements: []string{"Globalattribute"},
+		Name:   "contenteditable",
+		Global: true,
 	},
 	"contextmenu": Attribute{
-		Name:     "contextmenu",
-		Elements: []string{"Globalattribute"},
+		Name:   "contextmenu",
+		Global: true,
 	},
 	"controls": Attribute{
 		Name:     "controls",
@@ -140,8 +145,8 @@ var Map = map[string]Attribute{
 		Name:     "crossorigin",
 		Elements: []string{"audio", "img", "link", "script", "video"},
 	},
-	"csp  ": Attribute{
-		Name:     "csp  ",
+	"csp": Attribute{
+		Name:     "csp",
 		Elements: []string{"iframe"},
 	},
 	"data": Attribute{
@@ -149,8 +154,8 @@ var Map = map[string]Attribute{
 		Elements: []string{"object"},
 	},
 	"data-*": Attribute{
-		Name:     "data-*",
-		Elements: []string{"Globalattribute"},
+		Name:   "data-*",
+		Global: true,
 	},
 	"datetime": Attribute{
 		Name:     "datetime",
@@ -169,8 +174,8 @@ var Map = map[string]Attribute{
 		Elements: []string{"script"},
 	},
 	"dir": Attribute{
-		Name:     "dir",
-		Elements: []string{"Globalattribute"},
+		Name:   "dir",
+		Global: true,
 	},
 	"dirname": Attribute{
 		Name:     "dirname",
@@ -185,20 +190,20 @@ var Map = map[string]Attribute{
 		Elements: []string{"a", "area"},
 	},
 	"draggable": Attribute{
-		Name:     "draggable",
-		Elements: []string{"Globalattribute"},
+		Name:   "draggable",
+		Global: true,
 	},
 	"dropzone": Attribute{
-		Name:     "dropzone",
-		Elements: []string{"Globalattribute"},
+		Name:   "dropzone",
+		Global: true,
 	},
 	"enctype": Attribute{
 		Name:     "enctype",
 		Elements: []string{"form"},
 	},
-	"enterkeyhint  ": Attribute{
-		Name:     "enterkeyhint  ",
-		Elements: []string{"textarea", "contenteditable"},
+	"enterkeyhint": Attribute{
+		Name:     "enterkeyhint",
+		Elements: []string{"textarea"},
 	},
 	"for": Attribute{
 		Name:     "for",
@@ -210,7 +215,7 @@ var Map = map[string]Attribute{
 	},
 	"formaction": Attribute{
 		Name:     "formaction",
-		Elements: []string{"input", "button"},
+		Elements: []string{"button", "input"},
 	},
 	"formenctype": Attribute{
 		Name:     "formenctype",
@@ -237,8 +242,8 @@ var Map = map[string]Attribute{
 		Elements: []string{"canvas", "embed", "iframe", "img", "input", "object", "video"},
 	},
 	"hidden": Attribute{
-		Name:     "hidden",
-		Elements: []string{"Globalattribute"},
+		Name:   "hidden",
+		Global: true,
 	},
 	"high": Attribute{
 		Name:     "high",
@@ -261,32 +266,32 @@ var Map = map[string]Attribute{
 		Elements: []string{"command"},
 	},
 	"id": Attribute{
-		Name:     "id",
-		Elements: []string{"Globalattribute"},
+		Name:   "id",
+		Global: true,
 	},
-	"importance  ": Attribute{
-		Name:     "importance  ",
+	"importance": Attribute{
+		Name:     "importance",
 		Elements: []string{"iframe", "img", "link", "script"},
 	},
+	"inputmode": Attribute{
+		Name:     "inputmode",
+		Elements: []string{"textarea"},
+	},
 	"integrity": Attribute{
 		Name:     "integrity",
 		Elements: []string{"link", "script"},
 	},
-	"intrinsicsize  ": Attribute{
-		Name:     "intrinsicsize  ",
+	"intrinsicsize": Attribute{
+		Name:     "intrinsicsize",
 		Elements: []string{"img"},
 	},
-	"inputmode": Attribute{
-		Name:     "inputmode",
-		Elements: []string{"textarea", "contenteditable"},
-	},
 	"ismap": Attribute{
 		Name:     "ismap",
 		Elements: []string{"img"},
 	},
 	"itemprop": Attribute{
-		Name:     "itemprop",
-		Elements: []string{"Globalattribute"},
+		Name:   "itemprop",
+		Global: true,
 	},
 	"keytype": Attribute{
 		Name:     "keytype",
@@ -298,24 +303,24 @@ var Map = map[string]Attribute{
 	},
 	"label": Attribute{
 		Name:     "label",
-		Elements: []string{"optgroup", " option", " track"},
+		Elements: []string{"optgroup", "option", "track"},
 	},
 	"lang": Attribute{
-		Name:     "lang",
-		Elements: []string{"Globalattribute"},
+		Name:   "lang",
+		Global: true,
 	},
 	"language": Attribute{
 		Name:     "language",
 		Elements: []string{"script"},
 	},
-	"loading  ": Attribute{
-		Name:     "loading  ",
-		Elements: []string{"img", "iframe"},
-	},
 	"list": Attribute{
 		Name:     "list",
 		Elements: []string{"input"},
 	},
+	"loading": Attribute{
+		Name:     "loading",
+		Elements: []string{"iframe", "img"},
+	},
 	"loop": Attribute{
 		Name:     "loop",
 		Elements: []string{"audio", "bgsound", "marquee", "video"},
@@ -336,10 +341,6 @@ var Map = map[string]Attribute{
 		Name:     "maxlength",
 		Elements: []string{"input", "textarea"},
 	},
-	"minlength": Attribute{
-		Name:     "minlength",
-		Elements: []string{"input", "textarea"},
-	},
 	"media": Attribute{
 		Name:     "media",
 		Elements: []string{"a", "area", "link", "source", "style"},
@@ -352,6 +353,10 @@ var Map = map[string]Attribute{
 		Name:     "min",
 		Elements: []string{"input", "meter"},
 	},
+	"minlength": Attribute{
+		Name:     "minlength",
+		Elements: []string{"input", "textarea"},
+	},
 	"multiple": Attribute{
 		Name:     "multiple",
 		Elements: []string{"input", "select"},
@@ -362,7 +367,7 @@ var Map = map[string]Attribute{
 	},
 	"name": Attribute{
 		Name:     "name",
-		Elements: []string{"button", "form", "fieldset", "iframe", "input", "keygen", "object", "output", "select", "textarea", "map", "meta", "param"},
+		Elements: []string{"button", "fieldset", "form", "iframe", "input", "keygen", "map", "meta", "object", "output", "param", "select", "textarea"},
 	},
 	"novalidate": Attribute{
 		Name:     "novalidate",
@@ -454,19 +459,19 @@ var Map = map[string]Attribute{
 	},
 	"sizes": Attribute{
 		Name:     "sizes",
-		Elements: []string{"link", "img", "source"},
+		Elements: []string{"img", "link", "source"},
 	},
 	"slot": Attribute{
-		Name:     "slot",
-		Elements: []string{"Globalattribute"},
+		Name:   "slot",
+		Global: true,
 	},
 	"span": Attribute{
 		Name:     "span",
 		Elements: []string{"col", "colgroup"},
 	},
 	"spellcheck": Attribute{
-		Name:     "spellcheck",
-		Elements: []string{"Globalattribute"},
+		Name:   "spellcheck",
+		Global: true,
 	},
 	"src": Attribute{
 		Name:     "src",
@@ -493,32 +498,32 @@ var Map = map[string]Attribute{
 		Elements: []string{"input"},
 	},
 	"style": Attribute{
-		Name:     "style",
-		Elements: []string{"Globalattribute"},
+		Name:   "style",
+		Global: true,
 	},
 	"summary": Attribute{
 		Name:     "summary",
 		Elements: []string{"table"},
 	},
 	"tabindex": Attribute{
-		Name:     "tabindex",
-		Elements: []string{"Globalattribute"},
+		Name:   "tabindex",
+		Global: true,
 	},
 	"target": Attribute{
 		Name:     "target",
 		Elements: []string{"a", "area", "base", "form"},
 	},
 	"title": Attribute{
-		Name:     "title",
-		Elements: []string{"Globalattribute"},
+		Name:   "title",
+		Global: true,
 	},
 	"translate": Attribute{
-		Name:     "translate",
-		Elements: []string{"Globalattribute"},
+		Name:   "translate",
+		Global: true,
 	},
 	"type": Attribute{
 		Name:     "type",
-		Elements: []string{"button", "input", "command", "embed", "object", "script", "source", "style", "menu"},
+		Elements: []string{"button", "command", "embed", "input", "menu", "object", "script", "source", "style"},
 	},
 	"usemap": Attribute{
 		Name:     "usemap",
@@ -526,7 +531,7 @@ var Map = map[string]Attribute{
 	},
 	"value": Attribute{
 		Name:     "value",
-		Elements: []string{"button", "data", "input", "li", "meter", "option", "progress", "param"},
+		Elements: []string{"button", "data", "input", "li", "meter", "option", "param", "progress"},
 	},
 	"width": Attribute{
 		Name:     "width",
@@ -0,0 +1,82 @@
+// Package generator exposes the greact_gen command so projects can build their
+// own generator with custom template directives.
+//
+//	package main
+//
+//	import (
+//		"github.com/gernest/greact/cmd/generator"
+//		"github.com/gernest/greact/node"
+//	)
+//
+//	func main() {
+//		generator.MustRegister(generator.Directive{
+//			Prefix: "g-tooltip",
+//			Usage:  "sets the title of an element",
+//			Node: func(nd *node.Node, attr node.Attribute) error {
+//				nd.Attr = append(nd.Attr, node.Attribute{Key: "title", Val: attr.Val})
+//				return nil
+//			},
+//		})
+//		generator.Main()
+//	}
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"os"
+
+	"github.com/gernest/greact/cmd/internal/gen"
+	"github.com/gernest/greact/cmd/internal/server"
+	"github.com/urfave/cli"
+)
+
+// Directive is a custom template attribute, see Register.
+type Directive = gen.Directive
+
+// Register registers a template directive. Elements with attributes whose keys
+// start with the prefix of the directive are passed to it when code is
+// generated.
+func Register(d Directive) error {
+	return gen.RegisterDirective(d)
+}
+
+// MustRegister is like Register but panics on error.
+func MustRegister(d Directive) {
+	if err := Register(d); err != nil {
+		panic(err)
+	}
+}
+
+// ValueExpr returns the go expression of an attribute value as it is passed to
+// node.New.
+func ValueExpr(v interface{}) (ast.Expr, error) {
+	return gen.ValueExpr(v)
+}
+
+// App returns the greact_gen command line application.
+func App() *cli.App {
+	a := cli.NewApp()
+	a.Name = "greact_gen"
+	a.Usage = "provides various commands that generate code for greact project"
+	a.Commands = []cli.Command{
+		gen.AttrCMD(),
+		gen.RenderCMD(),
+		gen.ElementsCMD(),
+		gen.SpecCMD(),
+		gen.DirectivesCMD(),
+		server.Serve(),
+		server.Build(),
+		server.Size(),
+		server.Static(),
+	}
+	return a
+}
+
+// Main runs App with the command line arguments and exits on error.
+func Main() {
+	if err := App().Run(os.Args); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,704 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"html"
+	"path/filepath"
+	"strings"
+
+	"github.com/gernest/greact/expr"
+	"github.com/gernest/greact/node"
+)
+
+// checkPrefix is the name prefix of the methods generated for type checking
+// template expressions.
+const checkPrefix = "greactCheck"
+
+// origin describes where a checked expression came from in the template.
+type origin struct {
+	ctx *GeneratorContext
+
+	// text is the expression as it appears in the template, without the
+	// surrounding braces.
+	text string
+
+	// attr is the attribute the expression is the value of, it is empty for
+	// expressions in text nodes.
+	attr string
+
+	// whole is true when the expression is the whole value of attr.
+	whole bool
+
+	// component is set when the origin is a component tag rather than an
+	// expression.
+	component string
+
+	// target is set when attr is a prop of a component, it is the name of the
+	// component.
+	target string
+
+	// plain is true when text is not an expression, this is the case for props
+	// whose value is text.
+	plain bool
+
+	// tag is the tag of the component, as parsed, when component is set.
+	tag string
+
+	// offset is where the origin starts in the template source, it is -1 when
+	// it is unknown.
+	offset int
+}
+
+// position returns where o starts in the template source.
+func (o origin) position() token.Position {
+	pos := o.ctx.Pos
+	if pos.Filename == "" || o.offset < 0 {
+		return pos
+	}
+	before := o.ctx.Template[:o.offset]
+	if n := strings.Count(before, "\n"); n > 0 {
+		pos.Line += n
+		pos.Column = o.offset - strings.LastIndex(before, "\n")
+	} else {
+		pos.Column += o.offset
+	}
+	return pos
+}
+
+// mark is a tag, an attribute or an expression found in a template source.
+type mark struct {
+	offset int
+	tag    string
+	attr   string
+	expr   string
+	spread bool
+
+	// owner is the index of the attribute mark an expression is the value of,
+	// it is -1 for expressions in text.
+	owner int
+}
+
+// templateMarks returns tags, attribute names and expressions of src in the
+// order they appear. The html parser keeps no positions, nodes are visited in
+// the same order so the marks give them their place in the source. Names are
+// lower cased and expressions unescaped like the parser does.
+func templateMarks(src string) []mark {
+	var marks []mark
+	inTag := false
+	owner := -1
+	scan := func(i int, unescape bool) (int, bool) {
+		text, size, err := expr.ScanExpression(src[i:], '{', '}')
+		if err != nil {
+			return 0, false
+		}
+		m := mark{offset: i, expr: text, owner: owner}
+		if unescape {
+			m.expr = html.UnescapeString(text)
+		}
+		if inTag && owner == -1 && strings.HasPrefix(text, "...") {
+			m.spread = true
+			m.expr = strings.TrimSpace(strings.TrimPrefix(text, "..."))
+		}
+		marks = append(marks, m)
+		return size, true
+	}
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case !inTag && strings.HasPrefix(src[i:], "<!--"):
+			end := strings.Index(src[i+4:], "-->")
+			if end == -1 {
+				return marks
+			}
+			i += end + 7
+			continue
+		case !inTag && c == '<' && i+1 < len(src) && isASCIILetter(src[i+1]):
+			j := i + 1
+			for j < len(src) && isAttrNameByte(src[j]) {
+				j++
+			}
+			marks = append(marks, mark{offset: i, tag: strings.ToLower(src[i+1 : j]), owner: -1})
+			inTag = true
+			i = j
+			continue
+		case !inTag && (c == '{' || c == '}') && i+1 < len(src) && src[i+1] == c:
+			// escaped braces.
+			i += 2
+			continue
+		case !inTag && c == '{':
+			size, ok := scan(i, true)
+			if !ok {
+				return marks
+			}
+			i += size
+			continue
+		case inTag && c == '>':
+			inTag = false
+			owner = -1
+		case inTag && (c == '"' || c == '\''):
+			end := i + 1 + strings.IndexByte(src[i+1:], c)
+			if end == i {
+				return marks
+			}
+			for j := i + 1; j < end; {
+				if src[j] == '{' && src[j+1] == '{' {
+					j += 2
+					continue
+				}
+				if src[j] != '{' {
+					j++
+					continue
+				}
+				size, ok := scan(j, true)
+				if !ok {
+					return marks
+				}
+				j += size
+			}
+			owner = -1
+			i = end + 1
+			continue
+		case inTag && c == '{':
+			if i == 0 || src[i-1] != '=' {
+				// a spread, not the value of the last attribute.
+				owner = -1
+			}
+			size, ok := scan(i, false)
+			if !ok {
+				return marks
+			}
+			owner = -1
+			i += size
+			continue
+		case inTag && isAttrNameByte(c) && c != '/':
+			j := i
+			for j < len(src) && isAttrNameByte(src[j]) {
+				j++
+			}
+			owner = len(marks)
+			marks = append(marks, mark{offset: i, attr: strings.ToLower(src[i:j]), owner: -1})
+			i = j
+			continue
+		}
+		i++
+	}
+	return marks
+}
+
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isAttrNameByte(c byte) bool {
+	return c > ' ' && c != '=' && c != '>' && c != '"' && c != '\'' && c != '{'
+}
+
+// locate sets offsets of origins of ctx, which are in the order of the
+// template. Origins are matched to the marks of the template moving forward
+// only, so repeated expressions get the position of their own occurrence.
+func locate(ctx *GeneratorContext, origins []origin) {
+	marks := templateMarks(ctx.Template)
+	next := 0
+	find := func(match func(m mark) bool) int {
+		for i := next; i < len(marks); i++ {
+			if match(marks[i]) {
+				next = i + 1
+				return i
+			}
+		}
+		return -1
+	}
+	for k := range origins {
+		o := &origins[k]
+		o.offset = -1
+		attr := strings.ToLower(o.attr)
+		i := -1
+		switch {
+		case o.component != "":
+			tag := strings.ToLower(o.tag)
+			i = find(func(m mark) bool { return m.tag == tag })
+		case o.attr == node.SpreadKey:
+			i = find(func(m mark) bool { return m.spread && m.expr == o.text })
+		case o.plain:
+			i = find(func(m mark) bool { return m.attr == attr })
+		case o.attr != "":
+			i = find(func(m mark) bool {
+				return m.owner != -1 && marks[m.owner].attr == attr && m.expr == o.text
+			})
+			if i != -1 {
+				i = marks[i].owner
+			}
+		default:
+			i = find(func(m mark) bool {
+				return m.owner == -1 && !m.spread && m.tag == "" && m.attr == "" && m.expr == o.text
+			})
+		}
+		if i != -1 {
+			o.offset = marks[i].offset
+		}
+	}
+}
+
+func (o origin) describe() string {
+	switch {
+	case o.component != "":
+		return fmt.Sprintf("<%s>", o.component)
+	case o.attr == node.SpreadKey:
+		return fmt.Sprintf("{...%s}", o.text)
+	case o.plain:
+		return fmt.Sprintf("%s=%q", o.attr, o.text)
+	case o.attr != "":
+		return fmt.Sprintf("%s={%s}", o.attr, o.text)
+	default:
+		return fmt.Sprintf("{%s}", o.text)
+	}
+}
+
+// TemplateError is returned when template expressions of a component fail to
+// type check. Errors in generated code that can't be traced to an expression
+// have no Source, those outside of components have no Component either and
+// point to the generated file.
+type TemplateError struct {
+	Pos       token.Position
+	Component string
+	Source    string
+	Msg       string
+}
+
+func (e *TemplateError) Error() string {
+	s := e.Pos.String()
+	for _, v := range []string{e.Component, e.Source} {
+		if v != "" {
+			s += ": " + v
+		}
+	}
+	return s + ": " + e.Msg
+}
+
+// TemplateErrors is a list of *TemplateError.
+type TemplateErrors []*TemplateError
+
+func (e TemplateErrors) Error() string {
+	var s []string
+	for _, v := range e {
+		s = append(s, v.Error())
+	}
+	return strings.Join(s, "\n")
+}
+
+// typeCheck checks the package files together with the generated render
+// source. Errors that originate from templates are reported, then errors in
+// the generated source which point to the template expressions they come from
+// when possible. The rest of the package is left for the compiler to complain
+// about.
+func typeCheck(fs *token.FileSet, dir string, pkg *ast.Package, generated []byte, m map[string]string, ctx []GeneratorContext) error {
+	src, origins, err := checkSource(pkg.Name, m, ctx)
+	if err != nil {
+		return err
+	}
+	files := sortedFiles(pkg)
+	gen, err := parser.ParseFile(fs, renderFileName(dir, pkg.Name), generated, 0)
+	if err != nil {
+		return err
+	}
+	checkFile := filepath.Join(dir, fmt.Sprintf("%s_render_check.go", pkg.Name))
+	check, err := parser.ParseFile(fs, checkFile, src, 0)
+	if err != nil {
+		return err
+	}
+	files = append(files, gen, check)
+
+	var errs []types.Error
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fs, "source", nil),
+		Error: func(err error) {
+			if e, ok := err.(types.Error); ok {
+				errs = append(errs, e)
+			}
+		},
+	}
+	tpkg, _ := conf.Check(pkg.Name, fs, files, info)
+
+	funcs := make(map[string]*ast.FuncDecl)
+	for _, d := range check.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok {
+			funcs[fn.Name.Name] = fn
+		}
+	}
+	var result TemplateErrors
+	report := func(o origin, msg string) {
+		result = append(result, &TemplateError{
+			Pos:       o.position(),
+			Component: o.ctx.StructName,
+			Source:    o.describe(),
+			Msg:       msg,
+		})
+	}
+	for _, e := range errs {
+		if e.Soft {
+			continue
+		}
+		if e.Fset.File(e.Pos) != fs.File(check.Pos()) {
+			continue
+		}
+		for i, o := range origins {
+			fn := funcs[checkName(i)]
+			if fn != nil && fn.Pos() <= e.Pos && e.Pos <= fn.End() {
+				report(o, e.Msg)
+				break
+			}
+		}
+	}
+	if len(result) > 0 {
+		return result
+	}
+	// The generated source must compile, soft errors like unused imports
+	// included.
+	mapped := newMappedFile(fs, gen, ctx, origins)
+	for _, e := range errs {
+		if e.Fset.File(e.Pos) != fs.File(gen.Pos()) {
+			continue
+		}
+		pos := e.Fset.Position(e.Pos)
+		c, o := mapped.find(e.Pos)
+		switch {
+		case o != nil:
+			report(*o, fmt.Sprintf("%s (generated %s:%d:%d)", e.Msg, filepath.Base(pos.Filename), pos.Line, pos.Column))
+		case c != nil:
+			result = append(result, &TemplateError{
+				Pos:       c.Pos,
+				Component: c.StructName,
+				Msg:       fmt.Sprintf("%s (generated %s:%d:%d)", e.Msg, filepath.Base(pos.Filename), pos.Line, pos.Column),
+			})
+		default:
+			result = append(result, &TemplateError{Pos: pos, Msg: e.Msg})
+		}
+	}
+	if len(result) > 0 {
+		return result
+	}
+	for i, o := range origins {
+		if o.target != "" {
+			checkProp(tpkg, info, o, funcs[checkName(i)], report)
+			continue
+		}
+		if !o.whole {
+			continue
+		}
+		fn := funcs[checkName(i)]
+		if fn == nil {
+			continue
+		}
+		typ := info.TypeOf(checkedExpr(fn))
+		if typ == nil {
+			continue
+		}
+		key := strings.ToLower(o.attr)
+		switch {
+		case key == node.SpreadKey:
+			if !spreadTypes[types.TypeString(typ, nil)] {
+				report(o, fmt.Sprintf("cannot spread %s, expected []node.Attribute, greact.Props, map[string]interface{} or map[string]string", typ))
+			}
+		case key == "class":
+			if !classTypes[types.TypeString(typ, nil)] {
+				report(o, fmt.Sprintf("class must be a string, []string, map[string]bool or node.ClassList, got %s", typ))
+			}
+		case key == "style":
+			if !styleType(typ) {
+				report(o, fmt.Sprintf("style must be a string, map or struct of css properties, got %s", typ))
+			}
+		case strings.HasPrefix(key, node.ClassPrefix):
+			if b, ok := typ.Underlying().(*types.Basic); !ok || b.Info()&types.IsBoolean == 0 {
+				report(o, fmt.Sprintf("%s must be a bool, got %s", o.attr, typ))
+			}
+		case strings.HasPrefix(key, "on"):
+			if _, ok := typ.Underlying().(*types.Signature); !ok {
+				report(o, fmt.Sprintf("%s must be a func, got %s", o.attr, typ))
+			}
+		case node.IsBooleanAttr(key):
+			if b, ok := typ.Underlying().(*types.Basic); !ok || b.Info()&types.IsBoolean == 0 {
+				report(o, fmt.Sprintf("%s must be a bool, got %s", o.attr, typ))
+			}
+		}
+	}
+	if len(result) > 0 {
+		return result
+	}
+	return nil
+}
+
+// spreadTypes are types of values node.New expands in spread attributes.
+var spreadTypes = map[string]bool{
+	"[]github.com/gernest/greact/node.Attribute": true,
+	"github.com/gernest/greact/node.Props":       true,
+	"map[string]interface{}":                     true,
+	"map[string]string":                          true,
+}
+
+// classTypes are types of values node.Classes accepts.
+var classTypes = map[string]bool{
+	"string":          true,
+	"[]string":        true,
+	"map[string]bool": true,
+	"github.com/gernest/greact/node.ClassList": true,
+}
+
+// styleType returns true if node.Styles accepts values of typ.
+func styleType(typ types.Type) bool {
+	switch types.TypeString(typ, nil) {
+	case "string", "map[string]string", "map[string]interface{}",
+		"github.com/gernest/greact/node.Style":
+		return true
+	}
+	if p, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = p.Elem()
+	}
+	_, ok := typ.Underlying().(*types.Struct)
+	return ok
+}
+
+// checkProp checks the value of a prop passed to a component with typed props.
+// fn is the method checking the value expression, it is nil for text values.
+func checkProp(pkg *types.Package, info *types.Info, o origin, fn *ast.FuncDecl, report func(origin, string)) {
+	props := componentProps(pkg, o)
+	if props == nil {
+		return
+	}
+	var field *types.Var
+	for i := 0; i < props.NumFields(); i++ {
+		f := props.Field(i)
+		if !f.Exported() {
+			continue
+		}
+		if key, ok := propsKey(f.Name(), props.Tag(i)); ok && key == strings.ToLower(o.attr) {
+			field = f
+			break
+		}
+	}
+	if field == nil {
+		report(o, fmt.Sprintf("unknown prop %s for component %s", o.attr, o.target))
+		return
+	}
+	qualifier := types.RelativeTo(pkg)
+	if o.plain {
+		if !decodable(types.Typ[types.String], field.Type()) {
+			report(o, fmt.Sprintf("prop %s of %s expects %s, text is passed as string",
+				o.attr, o.target, types.TypeString(field.Type(), qualifier)))
+		}
+		return
+	}
+	if fn == nil {
+		return
+	}
+	typ := info.TypeOf(checkedExpr(fn))
+	if typ == nil {
+		return
+	}
+	if !decodable(typ, field.Type()) {
+		report(o, fmt.Sprintf("cannot use %s as %s value in prop %s of %s",
+			types.TypeString(typ, qualifier), types.TypeString(field.Type(), qualifier), o.attr, o.target))
+	}
+}
+
+// decodable returns true if a prop value of type v is decoded into a field of
+// type t. The generated decoder uses a type assertion, so apart from nil and
+// interface fields the types must be identical, an untyped constant like 3
+// is passed as int and won't decode into an int64.
+func decodable(v, t types.Type) bool {
+	if types.IsInterface(t) || v == types.Typ[types.UntypedNil] {
+		return types.AssignableTo(v, t)
+	}
+	return types.Identical(v, t)
+}
+
+// componentProps returns the props struct of the component targeted by o.
+// This returns nil if the component doesn't use typed props.
+func componentProps(pkg *types.Package, o origin) *types.Struct {
+	if pkg == nil {
+		return nil
+	}
+	scope := pkg.Scope()
+	name := o.target
+	if i := strings.Index(name, "."); i != -1 {
+		path := o.ctx.Imports[name[:i]]
+		scope = nil
+		for _, p := range pkg.Imports() {
+			if p.Path() == path {
+				scope = p.Scope()
+				break
+			}
+		}
+		if scope == nil {
+			return nil
+		}
+		name = name[i+1:]
+	}
+	obj, ok := scope.Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if f.Name() != propsField || f.Anonymous() {
+			continue
+		}
+		if _, ok := f.Type().(*types.Named); !ok {
+			return nil
+		}
+		props, _ := f.Type().Underlying().(*types.Struct)
+		return props
+	}
+	return nil
+}
+
+func checkName(i int) string {
+	return fmt.Sprintf("%s%d", checkPrefix, i)
+}
+
+// checkedExpr returns the expression whose value is the result of the
+// template expression checked by fn.
+func checkedExpr(fn *ast.FuncDecl) ast.Expr {
+	a, ok := fn.Body.List[0].(*ast.AssignStmt)
+	if !ok {
+		return nil
+	}
+	lit, ok := a.Rhs[0].(*ast.FuncLit)
+	if !ok {
+		return nil
+	}
+	n := len(lit.Body.List)
+	if n == 0 {
+		return nil
+	}
+	ret, ok := lit.Body.List[n-1].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return nil
+	}
+	return ret.Results[0]
+}
+
+// checkSource returns a go source file with a method for every expression
+// found in the templates. The methods share the signature of the generated
+// Render method so expressions are checked in the same scope they are going to
+// be evaluated in.
+func checkSource(pkg string, m map[string]string, ctx []GeneratorContext) ([]byte, []origin, error) {
+	var origins []origin
+	for i := range ctx {
+		c := &ctx[i]
+		if c.Node == nil {
+			continue
+		}
+		o, err := collectOrigins(c, m, c.Node)
+		if err != nil {
+			return nil, nil, err
+		}
+		locate(c, o)
+		origins = append(origins, o...)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n\t%q\n\t%q\n", "context", packageImport)
+	for _, spec := range generatedImports(ctx...) {
+		s := spec.(*ast.ImportSpec)
+		fmt.Fprintf(&buf, "\t%s %s\n", s.Name.Name, s.Path.Value)
+	}
+	buf.WriteString(")\n")
+	for i, o := range origins {
+		if o.plain {
+			continue
+		}
+		fmt.Fprintf(&buf, "\nfunc (%s *%s) %s(ctx context.Context, props %s.Props, state %s.State) {\n",
+			o.ctx.Recv, o.ctx.StructName, checkName(i), packageName, packageName)
+		if o.component != "" {
+			fmt.Fprintf(&buf, "\t_ = %s{}\n}\n", o.component)
+			continue
+		}
+		e, err := expr.Parse(o.text)
+		if err != nil {
+			return nil, nil, err
+		}
+		buf.WriteString("\t_ = ")
+		printer.Fprint(&buf, token.NewFileSet(), e)
+		buf.WriteString("\n}\n")
+	}
+	return buf.Bytes(), origins, nil
+}
+
+func collectOrigins(ctx *GeneratorContext, m map[string]string, nd *node.Node) ([]origin, error) {
+	var o []origin
+	if nd.Type == node.TextNode {
+		parts, err := expr.ExtractExpressions(nd.Data, '{', '}')
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range parts {
+			if !p.Plain {
+				o = append(o, origin{ctx: ctx, text: p.Text})
+			}
+		}
+	}
+	var target string
+	if isComponent(nd) {
+		target = componentName(m, nd.Data)
+		o = append(o, origin{ctx: ctx, component: target, tag: nd.Data})
+	}
+	for _, a := range nd.Attr {
+		s, ok := a.Val.(string)
+		if !ok {
+			continue
+		}
+		parts, err := expr.ExtractExpressions(s, '{', '}')
+		if err != nil {
+			return nil, err
+		}
+		// Spread, conditional class and directive attributes are not props,
+		// their values are only checked to be valid expressions.
+		_, directive := lookupDirective(a.Key)
+		if target != "" && a.Key != "key" && a.Key != node.SpreadKey &&
+			!strings.HasPrefix(a.Key, node.ClassPrefix) && !directive {
+			if len(parts) == 1 && !parts[0].Plain {
+				o = append(o, origin{
+					ctx:    ctx,
+					text:   parts[0].Text,
+					attr:   a.Key,
+					whole:  true,
+					target: target,
+				})
+				continue
+			}
+			o = append(o, origin{ctx: ctx, text: s, attr: a.Key, target: target, plain: true})
+		}
+		for _, p := range parts {
+			if !p.Plain {
+				o = append(o, origin{
+					ctx:   ctx,
+					text:  p.Text,
+					attr:  a.Key,
+					whole: len(parts) == 1,
+				})
+			}
+		}
+	}
+	for _, c := range nd.Children {
+		co, err := collectOrigins(ctx, m, c)
+		if err != nil {
+			return nil, err
+		}
+		o = append(o, co...)
+	}
+	return o, nil
+}
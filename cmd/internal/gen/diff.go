@@ -0,0 +1,190 @@
+package gen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around changes in a
+// unified diff.
+const contextLines = 3
+
+type diffOp byte
+
+const (
+	opEqual  diffOp = ' '
+	opDelete diffOp = '-'
+	opInsert diffOp = '+'
+)
+
+type diffLine struct {
+	op   diffOp
+	text string
+}
+
+// splitLines splits s into lines keeping the line endings.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the edit script that transforms a to b. It uses the
+// longest common subsequence of lines after trimming common prefix and suffix,
+// which is good enough for the size of generated files.
+func diffLines(a, b []string) []diffLine {
+	var prefix, suffix []diffLine
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		prefix = append(prefix, diffLine{opEqual, a[0]})
+		a, b = a[1:], b[1:]
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		suffix = append([]diffLine{{opEqual, a[len(a)-1]}}, suffix...)
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and
+	// b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	result := prefix
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, diffLine{opEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, diffLine{opDelete, a[i]})
+			i++
+		default:
+			result = append(result, diffLine{opInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		result = append(result, diffLine{opDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		result = append(result, diffLine{opInsert, b[j]})
+	}
+	return append(result, suffix...)
+}
+
+// unifiedDiff writes the difference between a and b in unified format. Nothing
+// is written when a and b are equal.
+func unifiedDiff(w io.Writer, nameA, nameB string, a, b []byte) error {
+	lines := diffLines(splitLines(string(a)), splitLines(string(b)))
+	changed := false
+	for _, l := range lines {
+		if l.op != opEqual {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", nameA, nameB); err != nil {
+		return err
+	}
+	for start := 0; start < len(lines); {
+		// find the next change
+		first := start
+		for first < len(lines) && lines[first].op == opEqual {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		begin := first - contextLines
+		if begin < start {
+			begin = start
+		}
+		// extend the hunk while changes are close enough to share context.
+		end := first
+		for end < len(lines) {
+			if lines[end].op != opEqual {
+				end++
+				continue
+			}
+			next := end
+			for next < len(lines) && lines[next].op == opEqual {
+				next++
+			}
+			if next == len(lines) || next-end > 2*contextLines {
+				break
+			}
+			end = next
+		}
+		stop := end + contextLines
+		if stop > len(lines) {
+			stop = len(lines)
+		}
+		if err := writeHunk(w, lines, begin, stop); err != nil {
+			return err
+		}
+		start = stop
+	}
+	return nil
+}
+
+func writeHunk(w io.Writer, lines []diffLine, begin, end int) error {
+	// line numbers in a and b where the hunk begins.
+	la, lb := 1, 1
+	for _, l := range lines[:begin] {
+		if l.op != opInsert {
+			la++
+		}
+		if l.op != opDelete {
+			lb++
+		}
+	}
+	na, nb := 0, 0
+	for _, l := range lines[begin:end] {
+		if l.op != opInsert {
+			na++
+		}
+		if l.op != opDelete {
+			nb++
+		}
+	}
+	if na == 0 {
+		la--
+	}
+	if nb == 0 {
+		lb--
+	}
+	if _, err := fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", la, na, lb, nb); err != nil {
+		return err
+	}
+	for _, l := range lines[begin:end] {
+		text := l.text
+		if !strings.HasSuffix(text, "\n") {
+			text += "\n\\ No newline at end of file\n"
+		}
+		if _, err := fmt.Fprintf(w, "%c%s", l.op, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/gernest/greact/node"
+	"github.com/urfave/cli"
+)
+
+// Directive is a custom template attribute handled by the generator. Elements
+// with attributes whose keys start with Prefix are passed to the directive
+// which can rewrite the element or the code generated for it.
+//
+//	gen.RegisterDirective(gen.Directive{
+//		Prefix: "g-tooltip",
+//		Usage:  "sets the title of an element",
+//		Node: func(nd *node.Node, attr node.Attribute) error {
+//			nd.Attr = append(nd.Attr, node.Attribute{Key: "title", Val: attr.Val})
+//			return nil
+//		},
+//	})
+//
+// Directive attributes are not rendered, their values are type checked like
+// other attributes when they are expressions.
+type Directive struct {
+	// Prefix is matched against attribute keys. When prefixes of more than one
+	// directive match, the longest one is used.
+	Prefix string
+
+	// Usage is a short description shown by the directives command.
+	Usage string
+
+	// Node is called with a copy of the element before code is generated for
+	// it, changes to the element only affect the generated code.
+	Node func(nd *node.Node, attr node.Attribute) error
+
+	// Expr is called with the expression creating the element and returns the
+	// expression to use instead. Use ValueExpr to get the expression of the
+	// attribute value.
+	Expr func(nd *node.Node, attr node.Attribute, e ast.Expr) (ast.Expr, error)
+}
+
+var directives struct {
+	sync.Mutex
+	list []Directive
+}
+
+// RegisterDirective registers d, this must be called before generating code.
+// It is an error to register a directive with an empty prefix or a prefix
+// that is already registered.
+func RegisterDirective(d Directive) error {
+	if d.Prefix == "" {
+		return fmt.Errorf("directive prefix can not be empty")
+	}
+	if d.Prefix != strings.ToLower(d.Prefix) {
+		return fmt.Errorf("directive prefix %q must be lower case, attribute names are lower cased by the html parser", d.Prefix)
+	}
+	if d.Node == nil && d.Expr == nil {
+		return fmt.Errorf("directive %s has neither Node nor Expr", d.Prefix)
+	}
+	directives.Lock()
+	defer directives.Unlock()
+	for _, v := range directives.list {
+		if v.Prefix == d.Prefix {
+			return fmt.Errorf("directive %s is already registered", d.Prefix)
+		}
+	}
+	directives.list = append(directives.list, d)
+	sort.SliceStable(directives.list, func(i, j int) bool {
+		return len(directives.list[i].Prefix) > len(directives.list[j].Prefix)
+	})
+	return nil
+}
+
+// Directives returns registered directives sorted by prefix.
+func Directives() []Directive {
+	directives.Lock()
+	defer directives.Unlock()
+	list := append([]Directive(nil), directives.list...)
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Prefix < list[j].Prefix
+	})
+	return list
+}
+
+// ValueExpr returns the go expression of an attribute value as it is passed to
+// node.New, a single {expr} is the expression itself.
+func ValueExpr(v interface{}) (ast.Expr, error) {
+	return interpretValue(v)
+}
+
+func lookupDirective(key string) (Directive, bool) {
+	directives.Lock()
+	defer directives.Unlock()
+	for _, v := range directives.list {
+		if strings.HasPrefix(key, v.Prefix) {
+			return v, true
+		}
+	}
+	return Directive{}, false
+}
+
+// hasDirective returns true if an attribute of nd is handled by a directive.
+func hasDirective(nd *node.Node) bool {
+	for _, a := range nd.Attr {
+		if _, ok := lookupDirective(a.Key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+type appliedDirective struct {
+	d    Directive
+	attr node.Attribute
+}
+
+// applyDirectives returns a copy of nd without directive attributes after the
+// Node hooks of the directives were applied to it. The directives are returned
+// in the order their attributes appear so their Expr hooks can be applied.
+func applyDirectives(nd *node.Node) (*node.Node, []appliedDirective, error) {
+	if nd.Type != node.ElementNode || !hasDirective(nd) {
+		return nd, nil, nil
+	}
+	cp := *nd
+	cp.Attr = nil
+	cp.Children = append([]*node.Node(nil), nd.Children...)
+	var applied []appliedDirective
+	for _, a := range nd.Attr {
+		d, ok := lookupDirective(a.Key)
+		if !ok {
+			cp.Attr = append(cp.Attr, a)
+			continue
+		}
+		applied = append(applied, appliedDirective{d: d, attr: a})
+	}
+	for _, v := range applied {
+		if v.d.Node == nil {
+			continue
+		}
+		if err := v.d.Node(&cp, v.attr); err != nil {
+			return nil, nil, fmt.Errorf("<%s %s>: %v", nd.Data, v.attr.Key, err)
+		}
+	}
+	return &cp, applied, nil
+}
+
+// DirectivesCMD returns the command listing registered directives.
+func DirectivesCMD() cli.Command {
+	return cli.Command{
+		Name:  "directives",
+		Usage: "lists template directives registered with the generator",
+		Action: func(ctx *cli.Context) error {
+			return listDirectives(os.Stdout)
+		},
+	}
+}
+
+func listDirectives(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, d := range Directives() {
+		fmt.Fprintf(tw, "%s\t%s\n", d.Prefix, d.Usage)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,194 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/gernest/greact/expr"
+	"github.com/gernest/greact/node"
+)
+
+const domImport = "github.com/gernest/greact/dom"
+
+// namespaces maps namespaces set by the html parser to the uri used to create
+// elements in them.
+var namespaces = map[string]string{
+	"svg":  "http://www.w3.org/2000/svg",
+	"math": "http://www.w3.org/1998/Math/MathML",
+}
+
+// domBuilder writes the body of a Create method. Nodes are created in create
+// and statements that set values of expressions bound to them go to update.
+type domBuilder struct {
+	create, update bytes.Buffer
+	nodes          int
+	bindings       int
+}
+
+func (b *domBuilder) node() string {
+	n := fmt.Sprintf("n%d", b.nodes)
+	b.nodes++
+	return n
+}
+
+func (b *domBuilder) binding() string {
+	n := fmt.Sprintf("b%d", b.bindings)
+	b.bindings++
+	return n
+}
+
+// build writes code creating nd and returns the name of the variable holding
+// the created node.
+func (b *domBuilder) build(nd *node.Node) (string, error) {
+	name := b.node()
+	switch nd.Type {
+	case node.TextNode:
+		if plain(nd.Data) {
+			fmt.Fprintf(&b.create, "%s := doc.Call(\"createTextNode\", %s)\n", name, strconv.Quote(plainText(nd.Data)))
+			return name, nil
+		}
+		e, err := interpretText(nd.Data)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b.create, "%s := doc.Call(\"createTextNode\", \"\")\n", name)
+		fmt.Fprintf(&b.update, "%s.Text(%s, %s)\n", b.binding(), name, e)
+		return name, nil
+	case node.CommentNode:
+		fmt.Fprintf(&b.create, "%s := doc.Call(\"createComment\", %s)\n", name, strconv.Quote(nd.Data))
+		return name, nil
+	case node.ElementNode:
+	default:
+		return "", fmt.Errorf("unexpected %s in dom mode", nd.Type)
+	}
+	if isComponent(nd) {
+		return "", fmt.Errorf("<%s>: components are not supported in dom mode", nd.Data)
+	}
+	nd, applied, err := applyDirectives(nd)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range applied {
+		if v.d.Expr != nil {
+			return "", fmt.Errorf("<%s %s>: directive %s rewrites node expressions which is not supported in dom mode",
+				nd.Data, v.attr.Key, v.d.Prefix)
+		}
+	}
+	if ns, ok := namespaces[nd.Namespace]; ok {
+		fmt.Fprintf(&b.create, "%s := doc.Call(\"createElementNS\", %q, %q)\n", name, ns, nd.Data)
+	} else {
+		fmt.Fprintf(&b.create, "%s := doc.Call(\"createElement\", %q)\n", name, nd.Data)
+	}
+	for _, a := range nd.Attr {
+		s, _ := a.Val.(string)
+		switch {
+		case a.Key == node.SpreadKey:
+			return "", fmt.Errorf("<%s>: spread attributes are not supported in dom mode", nd.Data)
+		case a.Key == "key":
+		case plain(s) && !strings.HasPrefix(a.Key, node.ClassPrefix):
+			fmt.Fprintf(&b.create, "%s.Call(\"setAttribute\", %q, %s)\n", name, a.Key, strconv.Quote(plainText(s)))
+		default:
+			e, err := interpretValue(a.Val)
+			if err != nil {
+				return "", err
+			}
+			v, err := exprString(e)
+			if err != nil {
+				return "", err
+			}
+			switch {
+			case strings.HasPrefix(a.Key, node.ClassPrefix):
+				fmt.Fprintf(&b.update, "%s.Toggle(%s, %q, %s)\n", b.binding(), name, strings.TrimPrefix(a.Key, node.ClassPrefix), v)
+			case strings.HasPrefix(a.Key, "on") && !isText(s):
+				fmt.Fprintf(&b.update, "%s.Listen(%s, %q, %s)\n", b.binding(), name, strings.TrimPrefix(a.Key, "on"), v)
+			default:
+				fmt.Fprintf(&b.update, "%s.Attr(%s, %q, %s)\n", b.binding(), name, a.Key, v)
+			}
+		}
+	}
+	for _, c := range nd.Children {
+		child, err := b.build(c)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b.create, "%s.Call(\"appendChild\", %s)\n", name, child)
+	}
+	return name, nil
+}
+
+// plainText returns src with escaped braces replaced, src must have no
+// expressions.
+func plainText(src string) string {
+	parts, _ := expr.ExtractExpressions(src, '{', '}')
+	var buf bytes.Buffer
+	for _, p := range parts {
+		buf.WriteString(p.Text)
+	}
+	return buf.String()
+}
+
+// isText returns true if src has text around its expressions.
+func isText(src string) bool {
+	parts, _ := expr.ExtractExpressions(src, '{', '}')
+	return len(parts) != 1
+}
+
+func exprString(e ast.Expr) (string, error) {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), e); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// createDOM returns the source of the Create method of the component in ctx.
+// Instead of building nodes that are diffed by the reconciler, the method
+// builds the DOM of the template once and returns a function that updates only
+// the nodes bound to expressions.
+//
+//	func (h *Hello) Create(doc dom.Value, props greact.Props, state greact.State) (dom.Value, func(greact.Props, greact.State)) {
+//		n0 := doc.Call("createElement", "p")
+//		n1 := doc.Call("createTextNode", "")
+//		n0.Call("appendChild", n1)
+//		var b0 dom.Binding
+//		update := func(props greact.Props, state greact.State) {
+//			b0.Text(n1, expr.Eval(func() interface{} {
+//				return h.Name
+//			}))
+//		}
+//		update(props, state)
+//		return n0, update
+//	}
+func createDOM(ctx GeneratorContext, pre ...ast.Stmt) ([]byte, error) {
+	b := &domBuilder{}
+	root, err := b.build(ctx.Node)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", ctx.StructName, err)
+	}
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "\nfunc (%s *%s) Create(doc dom.Value, props %s.Props, state %s.State) (dom.Value, func(%s.Props, %s.State)) {\n",
+		ctx.Recv, ctx.StructName, packageName, packageName, packageName, packageName)
+	src.Write(b.create.Bytes())
+	if b.bindings > 0 {
+		var names []string
+		for i := 0; i < b.bindings; i++ {
+			names = append(names, fmt.Sprintf("b%d", i))
+		}
+		fmt.Fprintf(&src, "var %s dom.Binding\n", strings.Join(names, ", "))
+	}
+	fmt.Fprintf(&src, "update := func(props %s.Props, state %s.State) {\n", packageName, packageName)
+	for _, s := range pre {
+		if err := printer.Fprint(&src, token.NewFileSet(), s); err != nil {
+			return nil, err
+		}
+		src.WriteString("\n")
+	}
+	src.Write(b.update.Bytes())
+	fmt.Fprintf(&src, "}\nupdate(props, state)\nreturn %s, update\n}\n", root)
+	return src.Bytes(), nil
+}
@@ -0,0 +1,14 @@
+package attrs
+
+import "github.com/gernest/greact"
+
+type Hello struct {
+	greact.Core
+	Name string
+}
+
+func (h Hello) Template() string {
+	return `<p onclick={h.Name} hidden={h.Name}>{h.Name}</p>
+<p class:active={h.Name} {...h.Name}></p>
+<p class={1} style={h.Name == ""}></p>`
+}
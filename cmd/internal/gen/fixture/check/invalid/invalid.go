@@ -0,0 +1,15 @@
+package invalid
+
+import "github.com/gernest/greact"
+
+type Hello struct {
+	greact.Core
+	Name string
+}
+
+func (h Hello) Template() string {
+	return `<div>
+	<p onclick={h.Name} hidden={h.Name}>{h.Nmae}</p>
+	<Missing></Missing>
+</div>`
+}
@@ -0,0 +1,21 @@
+package valid
+
+import "github.com/gernest/greact"
+
+type Hello struct {
+	greact.Core
+	Name     string
+	Hidden   bool
+	Selected bool
+	OnClick  func()
+	Attrs    map[string]interface{}
+	Classes  map[string]bool
+	Style    struct{ Color string }
+}
+
+func (h Hello) Template() string {
+	return `<div onclick={h.OnClick} hidden={h.Hidden} class:selected={h.Selected} {...h.Attrs}>
+	<p class={h.Classes} style={h.Style}>{h.Name}</p>
+	<p class={[]string{"a", h.Name}} style={&h.Style}></p>
+</div>`
+}
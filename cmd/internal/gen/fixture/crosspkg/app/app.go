@@ -0,0 +1,21 @@
+package app
+
+import (
+	"github.com/gernest/greact"
+	kit "github.com/gernest/greact/cmd/internal/gen/fixture/crosspkg/ui"
+)
+
+//greact:import ui "github.com/gernest/greact/cmd/internal/gen/fixture/crosspkg/ui"
+
+var _ = kit.Icon{}
+
+type Page struct {
+	greact.Core
+}
+
+func (p Page) Template() string {
+	return `<div>
+	<ui.Button></ui.Button>
+	<kit.Icon></kit.Icon>
+</div>`
+}
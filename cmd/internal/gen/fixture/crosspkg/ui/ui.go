@@ -0,0 +1,19 @@
+package ui
+
+import "github.com/gernest/greact"
+
+type Button struct {
+	greact.Core
+}
+
+func (b Button) Template() string {
+	return `<button></button>`
+}
+
+type Icon struct {
+	greact.Core
+}
+
+func (i Icon) Template() string {
+	return `<i></i>`
+}
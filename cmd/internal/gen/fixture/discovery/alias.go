@@ -0,0 +1,34 @@
+package discovery
+
+import (
+	"fmt"
+
+	g "github.com/gernest/greact"
+)
+
+const pointerTemplate = `<p>{p.Name}</p>`
+
+// Pointer declares Template with a pointer receiver and returns a named
+// constant.
+type Pointer struct {
+	g.Core
+	Name string
+}
+
+func (p *Pointer) Template() string {
+	return pointerTemplate
+}
+
+// NoTemplate has no template, it is skipped with a warning.
+type NoTemplate struct {
+	g.Core
+}
+
+// Dynamic has a template that is not constant, it is skipped with a warning.
+type Dynamic struct {
+	g.Core
+}
+
+func (d Dynamic) Template() string {
+	return fmt.Sprint("<p></p>")
+}
@@ -0,0 +1,6 @@
+package discovery
+
+func (c Concat) Template() string {
+	const tail = "</div>"
+	return head + `<span>{c.Name}</span>` + tail
+}
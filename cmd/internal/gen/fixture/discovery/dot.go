@@ -0,0 +1,11 @@
+package discovery
+
+import . "github.com/gernest/greact"
+
+const head = "<div>"
+
+// Concat is declared here but its Template method lives in concat.go.
+type Concat struct {
+	Core
+	Name string
+}
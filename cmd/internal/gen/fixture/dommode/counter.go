@@ -0,0 +1,25 @@
+package dommode
+
+import "github.com/gernest/greact"
+
+// Counter is rendered without the virtual dom.
+//
+//greact:dom
+type Counter struct {
+	greact.Core
+	Count    int
+	Selected bool
+}
+
+func (c *Counter) increment() {
+	c.Count++
+}
+
+func (c *Counter) Template() string {
+	return `<div class="counter" class:selected={c.Selected}>
+	<h1>Counter</h1>
+	<p title="count {c.Count}">Count: {c.Count}</p>
+	<button onclick={c.increment} disabled={c.Count > 9}>+</button>
+	<svg><circle r={c.Count}></circle></svg>
+</div>`
+}
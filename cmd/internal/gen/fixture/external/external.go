@@ -0,0 +1,22 @@
+package external
+
+import "github.com/gernest/greact"
+
+// Hello uses hello.html by convention.
+type Hello struct {
+	greact.Core
+	Name string
+}
+
+// Card uses the file named by the directive.
+//
+//greact:template card_template.gohtml
+type Card struct {
+	greact.Core
+	Title string
+}
+
+// UserProfile uses user_profile.html by convention.
+type UserProfile struct {
+	greact.Core
+}
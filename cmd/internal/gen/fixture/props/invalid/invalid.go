@@ -0,0 +1,33 @@
+package invalid
+
+import "github.com/gernest/greact"
+
+type FancyButtonProps struct {
+	Label string
+	Size  int
+	Scale int64
+	Kind  Kind
+}
+
+type Kind string
+
+type FancyButton struct {
+	greact.Core
+	Props FancyButtonProps
+}
+
+func (b FancyButton) Template() string {
+	return `<button>{b.Props.Label}</button>`
+}
+
+type Page struct {
+	greact.Core
+}
+
+func (p Page) Template() string {
+	return `<div>
+	<FancyButton lable="Save"></FancyButton>
+	<FancyButton size="3" label={4}></FancyButton>
+	<FancyButton scale={3} kind="primary"></FancyButton>
+</div>`
+}
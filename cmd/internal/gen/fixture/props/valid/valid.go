@@ -0,0 +1,46 @@
+package valid
+
+import (
+	"time"
+
+	"github.com/gernest/greact"
+)
+
+type FancyButtonProps struct {
+	Label    string `greact:"label" default:"Click"`
+	Size     int    `default:"2"`
+	Disabled bool
+	OnClick  func()
+	Ignored  string `greact:"-"`
+	Scale    int64
+	Kind     Kind
+	Delay    time.Duration
+}
+
+type Kind string
+
+const Primary Kind = "primary"
+
+type FancyButton struct {
+	greact.Core
+	Props FancyButtonProps
+}
+
+func (b FancyButton) Template() string {
+	return `<button disabled={b.Props.Disabled}>{b.Props.Label}</button>`
+}
+
+type Page struct {
+	greact.Core
+	Busy bool
+	Wait time.Duration
+}
+
+func (p Page) Template() string {
+	return `<div>
+	<FancyButton label="Save" size={3} disabled={p.Busy} onclick={p.save} scale={int64(3)} kind={Primary} delay={p.Wait}></FancyButton>
+	<FancyButton key="cancel" label="Cancel {p.Busy}"></FancyButton>
+</div>`
+}
+
+func (p *Page) save() {}
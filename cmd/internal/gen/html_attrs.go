@@ -5,8 +5,6 @@ import (
 	"go/format"
 	"io"
 	"io/ioutil"
-	"net/http"
-	"regexp"
 	"strings"
 	"text/template"
 
@@ -17,54 +15,43 @@ import (
 
 const mozAttributeReference = "https://developer.mozilla.org/en-US/docs/Web/HTML/Attributes"
 
-func downloadAttrs(dest string) error {
-	res, err := http.Get(mozAttributeReference)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-	b, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return err
-	}
-	return ioutil.WriteFile(dest, b, 0600)
-}
-
-type attribute struct {
-	Name     string
-	Elements []string
-}
-
-var elRegexp = regexp.MustCompile(`[\<\>\s]`)
-
-func extractAttr(doc *goquery.Document) ([]attribute, error) {
+// extractAttr returns the attributes listed in the attribute reference, the
+// names are cleaned by spec.clean.
+func extractAttr(doc *goquery.Document) []attribute {
 	var rst []attribute
 	table := doc.Find(".standard-table")
 	table.Find("tbody tr").Each(func(i int, sel *goquery.Selection) {
 		children := sel.Find("td")
 		a := children.Eq(0).Text()
 		e := children.Eq(1).Text()
-		e = elRegexp.ReplaceAllString(e, "")
-		parts := strings.Split(e, ",")
 		rst = append(rst, attribute{
 			Name:     a,
-			Elements: parts,
+			Elements: strings.Split(e, ","),
 		})
 	})
-	return rst, nil
+	return rst
 }
 
-const astr = `package attribute
+const astr = `// Code generated by greact_gen attr from html spec {{.Version}}. DO NOT EDIT.
+
+package attribute
+
 // Attribute represents htm attributes
 type Attribute struct{
 	Name string
+
+	// Global is true for attributes allowed on all elements.
+	Global bool
 	Elements []string
 }
 // Map maps html attribute name to the Attribute object.
 var Map =map[string]Attribute{
-	{{- range $k,$v:=. }}
+	{{- range .Attributes }}
 	"{{.Name}}": Attribute{
 		Name:"{{.Name}}",
+		{{- if .Global}}
+		Global: true,
+		{{- end}}
 		{{- with .Elements}}
 		Elements: []string{
 			{{- range . -}}
@@ -77,17 +64,14 @@ var Map =map[string]Attribute{
 }
 `
 
-func generateAttributes(w io.Writer, a []attribute) error {
+func generateAttributes(w io.Writer, s *spec) error {
 	tpl, err := template.New("a").Funcs(template.FuncMap{
 		"toAtom": toAtom,
-		"len": func(a []attribute) int {
-			return len(a) - 1
-		},
 	}).Parse(astr)
 	if err != nil {
 		return err
 	}
-	return tpl.Execute(w, a)
+	return tpl.Execute(w, s)
 }
 
 func toAtom(e string) string {
@@ -103,19 +87,21 @@ func AttrCMD() cli.Command {
 				Name:  "out",
 				Value: "attribute/attributes.go",
 			},
+			cli.StringFlag{
+				Name:  "spec",
+				Usage: "html spec data to generate from, it is updated with the spec command",
+				Value: defaultSpec,
+			},
 		},
 		Action: func(ctx *cli.Context) error {
 			out := ctx.String("out")
-			var buf bytes.Buffer
-			q, err := goquery.NewDocument(mozAttributeReference)
-			if err != nil {
-				return err
-			}
-			a, err := extractAttr(q)
+			s, err := loadSpec(ctx.String("spec"))
 			if err != nil {
 				return err
 			}
-			err = generateAttributes(&buf, a)
+			s.clean()
+			var buf bytes.Buffer
+			err = generateAttributes(&buf, s)
 			if err != nil {
 				return err
 			}
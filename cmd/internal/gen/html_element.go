@@ -5,7 +5,6 @@ import (
 	"go/format"
 	"io/ioutil"
 	"regexp"
-	"sort"
 	"text/template"
 
 	"github.com/PuerkitoBio/goquery"
@@ -16,36 +15,20 @@ const mozElementReference = "https://developer.mozilla.org/en-US/docs/Web/HTML/E
 
 var re = regexp.MustCompile(`[\<\>]`)
 
-func extractElements(doc *goquery.Document) ([]byte, error) {
-	sel := doc.Find("td:first-child").Map(func(i int, s *goquery.Selection) string {
+// extractElements returns the names in the first column of the tables of
+// the element reference.
+func extractElements(doc *goquery.Document) []string {
+	return doc.Find("td:first-child").Map(func(i int, s *goquery.Selection) string {
 		return re.ReplaceAllString(s.Text(), "")
 	})
-	add := []string{
-		"base",
-		"section",
-		"h1",
-		"h2",
-		"h3",
-		"h4",
-		"h5",
-		"h6",
-		"iframe",
-	}
-	sel = append(sel, add...)
-	var ctx []string
-	m := make(map[string]bool)
-	for _, v := range sel {
-		if !m[v] {
-			ctx = append(ctx, v)
-			m[v] = true
-		}
-	}
-	sort.Strings(ctx)
+}
 
-	tpl, err := template.New("el").Parse(`package elements
+var elementsTemplate = template.Must(template.New("el").Parse(`// Code generated by greact_gen elems from html spec {{.Version}}. DO NOT EDIT.
+
+	package elements
 
 	var elems =map[string]struct{}{
-		{{- range .}}
+		{{- range .Elements}}
 		"{{.}}":struct{}{},
 		{{- end}}
 	}
@@ -54,13 +37,11 @@ func extractElements(doc *goquery.Document) ([]byte, error) {
 		_,ok:=elems[name]
 		return ok
 	}
-	`)
-	if err != nil {
-		return nil, err
-	}
+	`))
+
+func generateElements(s *spec) ([]byte, error) {
 	var buf bytes.Buffer
-	err = tpl.Execute(&buf, ctx)
-	if err != nil {
+	if err := elementsTemplate.Execute(&buf, s); err != nil {
 		return nil, err
 	}
 	return format.Source(buf.Bytes())
@@ -75,18 +56,23 @@ func ElementsCMD() cli.Command {
 				Name:  "out",
 				Value: "elements/elements.go",
 			},
+			cli.StringFlag{
+				Name:  "spec",
+				Usage: "html spec data to generate from, it is updated with the spec command",
+				Value: defaultSpec,
+			},
 		},
 		Action: func(ctx *cli.Context) error {
-			out := ctx.String("out")
-			q, err := goquery.NewDocument(mozElementReference)
+			s, err := loadSpec(ctx.String("spec"))
 			if err != nil {
 				return err
 			}
-			a, err := extractElements(q)
+			s.clean()
+			a, err := generateElements(s)
 			if err != nil {
 				return err
 			}
-			return ioutil.WriteFile(out, a, 0600)
+			return ioutil.WriteFile(ctx.String("out"), a, 0600)
 		},
 	}
 }
@@ -0,0 +1,246 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gernest/greact/node"
+)
+
+// reservedImports maps names used by the generated code to the paths they
+// import, templates can not use them to qualify components.
+var reservedImports = map[string]string{
+	"context":   "context",
+	"dom":       domImport,
+	packageName: packageImport,
+	"expr":      "github.com/gernest/greact/expr",
+	"node":      "github.com/gernest/greact/node",
+}
+
+// packageImports returns import paths of files keyed by the name they are
+// referred to in templates. Imports declared by //greact:import name "path"
+// comments take precedence over the ones imported by go files, which is
+// useful when the package is only used in templates.
+func packageImports(files []*ast.File) (map[string]string, error) {
+	m := make(map[string]string)
+	directives := make(map[string]string)
+	for _, f := range files {
+		for _, i := range f.Imports {
+			p, err := strconv.Unquote(i.Path.Value)
+			if err != nil {
+				continue
+			}
+			name := path.Base(p)
+			if i.Name != nil {
+				name = i.Name.Name
+			}
+			if name == "_" || name == "." {
+				continue
+			}
+			m[name] = p
+		}
+		for _, g := range f.Comments {
+			for _, c := range g.List {
+				if !strings.HasPrefix(c.Text, "//greact:import ") {
+					continue
+				}
+				parts := strings.Fields(strings.TrimPrefix(c.Text, "//greact:import "))
+				if len(parts) != 2 {
+					return nil, fmt.Errorf("invalid directive %q expected //greact:import name \"path\"", c.Text)
+				}
+				p, err := strconv.Unquote(parts[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid directive %q: %v", c.Text, err)
+				}
+				directives[parts[0]] = p
+			}
+		}
+	}
+	for k, v := range directives {
+		m[k] = v
+	}
+	return m, nil
+}
+
+// resolveImports finds components from other packages used by templates in
+// ctx. Such components are referred to by tags qualified with the name of the
+// package like <ui.Button>. m is updated to map the tags to qualified type
+// names and Imports of each context is set to the packages its template uses.
+func resolveImports(dir string, files []*ast.File, m map[string]string, ctx []GeneratorContext) error {
+	var imports map[string]string
+	pkgs := make(map[string]map[string]string)
+	for i := range ctx {
+		c := &ctx[i]
+		err := walkNodes(c.Node, func(nd *node.Node) error {
+			tag := nd.Data
+			if !isComponent(nd) {
+				return nil
+			}
+			dot := strings.Index(tag, ".")
+			if dot == -1 {
+				return nil
+			}
+			if imports == nil {
+				var err error
+				imports, err = packageImports(files)
+				if err != nil {
+					return err
+				}
+			}
+			q := tag[:dot]
+			p, ok := imports[q]
+			if !ok {
+				return fmt.Errorf("%s: <%s>: no import for %s", c.Pos, tag, q)
+			}
+			if _, ok := reservedImports[q]; ok {
+				return fmt.Errorf("%s: <%s>: %s is reserved for generated code, import the package under a different name", c.Pos, tag, q)
+			}
+			names, ok := pkgs[p]
+			if !ok {
+				var err error
+				names, err = importComponents(dir, p)
+				if err != nil {
+					return fmt.Errorf("%s: <%s>: %v", c.Pos, tag, err)
+				}
+				pkgs[p] = names
+			}
+			name, ok := names[tag[dot+1:]]
+			if !ok {
+				return fmt.Errorf("%s: <%s>: no component named %s in %s", c.Pos, tag, tag[dot+1:], p)
+			}
+			m[tag] = q + "." + name
+			if c.Imports == nil {
+				c.Imports = make(map[string]string)
+			}
+			c.Imports[q] = p
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkNodes(nd *node.Node, fn func(*node.Node) error) error {
+	if nd == nil {
+		return nil
+	}
+	if err := fn(nd); err != nil {
+		return err
+	}
+	for _, c := range nd.Children {
+		if err := walkNodes(c, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importComponents returns exported components of the package with import
+// path p, keyed by their lower cased names.
+func importComponents(dir, p string) (map[string]string, error) {
+	bp, err := build.Import(p, dir, build.FindOnly)
+	if err != nil {
+		return nil, err
+	}
+	fs := token.NewFileSet()
+	pkg, err := parsePackage(&build.Default, fs, bp.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("no go files in %s", bp.Dir)
+	}
+	names := make(map[string]string)
+	for _, f := range sortedFiles(pkg) {
+		core := greactName(f)
+		if core == "" {
+			continue
+		}
+		for _, d := range f.Decls {
+			g, ok := d.(*ast.GenDecl)
+			if !ok || g.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range g.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok || !ast.IsExported(ts.Name.Name) {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					if embedsCore(field, core) {
+						names[strings.ToLower(ts.Name.Name)] = ts.Name.Name
+					}
+				}
+			}
+		}
+	}
+	return names, nil
+}
+
+// generatedImports returns import specs for packages used by templates and
+// props structs in ctx sorted by path.
+func generatedImports(ctx ...GeneratorContext) []ast.Spec {
+	m := make(map[string]string)
+	for _, c := range ctx {
+		for k, v := range c.Imports {
+			m[k] = v
+		}
+		if c.Props != nil {
+			for k, v := range c.Props.Imports {
+				m[k] = v
+			}
+		}
+	}
+	var names []string
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if m[names[i]] != m[names[j]] {
+			return m[names[i]] < m[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	var specs []ast.Spec
+	for _, name := range names {
+		// The name is always explicit because the package name is not
+		// necessarily the last element of the path.
+		s := importSpec(m[name])
+		s.Name = &ast.Ident{Name: name}
+		specs = append(specs, s)
+	}
+	return specs
+}
+
+// appendImports appends to specs the imports of more that specs doesn't
+// already have under the same name.
+func appendImports(specs []ast.Spec, more ...ast.Spec) []ast.Spec {
+	seen := make(map[string]bool)
+	key := func(s *ast.ImportSpec) string {
+		p, _ := strconv.Unquote(s.Path.Value)
+		name := path.Base(p)
+		if s.Name != nil {
+			name = s.Name.Name
+		}
+		return name + " " + p
+	}
+	for _, s := range specs {
+		seen[key(s.(*ast.ImportSpec))] = true
+	}
+	for _, s := range more {
+		if k := key(s.(*ast.ImportSpec)); !seen[k] {
+			seen[k] = true
+			specs = append(specs, s)
+		}
+	}
+	return specs
+}
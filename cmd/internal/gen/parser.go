@@ -1,100 +1,49 @@
 package gen
 
 import (
+	"bytes"
 	"fmt"
 	"go/ast"
 	"go/format"
 	"go/parser"
 	"go/token"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/gernest/greact/elements"
 	"github.com/gernest/greact/expr"
 	"github.com/gernest/greact/node"
+	"github.com/gernest/greact/tmpl"
 	"golang.org/x/net/html"
-	"golang.org/x/net/html/atom"
 )
 
 const packageName = "greact"
 const packageImport = "github.com/gernest/greact"
 
 const (
-	newNode  = "createNode"
-	newAttr  = "createAttr"
-	newAttrs = "createAttrs"
+	newNode      = "createNode"
+	newComponent = "createComponent"
+	newAttr      = "createAttr"
+	newAttrs     = "createAttrs"
+	newStatic    = "createStatic"
 )
 
 // ToNode recursively transform n to a *Node.
 func ToNode(n *html.Node) *node.Node {
-	nd := &node.Node{
-		Type:      node.NodeType(uint32(n.Type)),
-		Data:      n.Data,
-		Namespace: n.Namespace,
-	}
-	for _, v := range n.Attr {
-		if v.Key == "" {
-			continue
-		}
-		nd.Attr = append(nd.Attr, node.Attribute{
-			Namespace: v.Namespace,
-			Key:       v.Key,
-			Val:       v.Val,
-		})
-	}
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		if c.Type == html.TextNode && strings.TrimSpace(c.Data) == "" {
-			continue
-		}
-		nd.Children = append(nd.Children, ToNode(c))
-	}
-	return nd
+	return tmpl.ToNode(n)
 }
 
 // Parse parses src as html component definition and returns their *Node
-// representation. r must be reading from a subset of xml/html document that is
-// going to processed and compiled to *Node.
+// representation, see tmpl.Parse.
 func Parse(r io.Reader) (*node.Node, error) {
-	base := root()
-	n, err := html.ParseFragment(r, base)
-	if err != nil {
-		return nil, err
-	}
-	var rst []*node.Node
-	for _, v := range n {
-		nd := ToNode(v)
-		if nd.Type == node.TextNode && strings.TrimSpace(nd.Data) == "" {
-			continue
-		}
-		rst = append(rst, nd)
-	}
-	container := &node.Node{
-		Type: node.ElementNode,
-		Data: "div",
-	}
-	switch len(rst) {
-	case 0:
-		return container, nil
-	case 1:
-		return rst[0], nil
-	default:
-		container.Children = rst
-		return container, nil
-	}
-}
-
-func root() *html.Node {
-	return &html.Node{
-		DataAtom: atom.Div,
-		Type:     html.ElementNode,
-		Data:     "div",
-	}
+	return tmpl.Parse(r)
 }
 
 // ParseString helper that wraps s to io.Reader.
 func ParseString(s string) (*node.Node, error) {
-	return Parse(strings.NewReader(s))
+	return tmpl.ParseString(s)
 }
 
 // process templates in text nodes
@@ -123,6 +72,48 @@ func interpret(v interface{}) (string, error) {
 	}
 }
 
+// interpretValue returns an expression that evaluates to the value of an
+// attribute without converting it to a string. This is only possible when the
+// value is a single template expression, plain text is used as a string and a
+// mix of the two is evaluated to a string.
+func interpretValue(v interface{}) (ast.Expr, error) {
+	s, ok := v.(string)
+	if !ok {
+		return &ast.Ident{Name: "nil"}, nil
+	}
+	parts, err := expr.ExtractExpressions(s, '{', '}')
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(parts) == 0:
+		return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote("")}, nil
+	case len(parts) == 1 && parts[0].Plain:
+		return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(parts[0].Text)}, nil
+	case len(parts) == 1:
+		if e, err := parser.ParseExpr(parts[0].Text); err == nil {
+			return e, nil
+		}
+		// The expression has statements, we call the function that wraps them.
+		e, err := expr.Parse(parts[0].Text)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.CallExpr{Fun: e}, nil
+	}
+	txt, err := expr.WrapString(parts...)
+	if err != nil {
+		return nil, err
+	}
+	return parser.ParseExpr(txt)
+}
+
+// isComponent returns true if nd is rendered by a component. Elements in
+// foreign namespaces like svg are never components.
+func isComponent(nd *node.Node) bool {
+	return nd.Type == node.ElementNode && nd.Namespace == "" && !elements.Valid(nd.Data)
+}
+
 func pickExpressions(src string) []expr.Expression {
 	return nil
 }
@@ -144,44 +135,146 @@ type GeneratorContext struct {
 
 	// The actual node we want to generate go ast for.
 	Node *node.Node
+
+	// Template is the source Node was parsed from and Pos is where it starts.
+	// They are used to point errors back to the template.
+	Template string
+	Pos      token.Position
+
+	// Imports maps names to import paths of packages whose components are used
+	// in the template.
+	Imports map[string]string
+
+	// Props is set when the component reads its props from a struct. The
+	// struct is stored in the Props field of the component and it is populated
+	// before rendering.
+	Props *TypedProps
+
+	// DOM is true for components whose templates are compiled to a Create
+	// method building the DOM directly instead of a Render method. It is set
+	// by the //greact:dom directive.
+	DOM bool
 }
 
 // Generate writes a g file that contains generated Render methods for struct
 // defined in the GeneratorContext.
 func Generate(w io.Writer, pkg string, m map[string]string, ctx ...GeneratorContext) error {
-	if m == nil {
-		m = make(map[string]string)
-	}
-	for _, c := range ctx {
-		if _, ok := m[c.StructName]; !ok {
-			m[strings.ToLower(c.StructName)] = c.StructName
+	m = componentMap(m, ctx...)
+	var imports []ast.Spec
+	// context is only used by Render methods, DOM mode components have none.
+	for _, v := range ctx {
+		if !v.DOM {
+			imports = append(imports, importSpec("context"))
+			break
 		}
 	}
+	imports = append(imports,
+		importSpec("github.com/gernest/greact"),
+		importSpec("github.com/gernest/greact/expr"),
+		importSpec("github.com/gernest/greact/node"),
+	)
 	file := &ast.File{
 		Name: &ast.Ident{
 			Name: pkg,
 		},
 		Decls: []ast.Decl{
-			importDecl(
-				importSpec("context"),
-				importSpec("github.com/gernest/greact"),
-				importSpec("github.com/gernest/greact/expr"),
-				importSpec("github.com/gernest/greact/node"),
-			),
+			importDecl(appendImports(imports, append(domImports(ctx...), generatedImports(ctx...)...)...)...),
 			declareAlias(newNode, "node", "New"),
+			declareAlias(newComponent, "node", "NewComponent"),
 			declareAlias(newAttr, "node", "Attr"),
 			declareAlias(newAttrs, "node", "Attrs"),
+			declareAlias(newStatic, "node", "Static"),
 			declareAlias("_", "expr", "Eval"),
 		},
 	}
+	decoders := make(map[string]*TypedProps)
+	var dom []byte
 	for _, v := range ctx {
-		e, err := renderNode("Render", v.Recv, v.StructName, v.Node, m)
+		var pre []ast.Stmt
+		if v.Props != nil {
+			pre = append(pre, decodeStmt(v.Recv, v.Props))
+			decoders[v.Props.Type] = v.Props
+		}
+		if v.DOM {
+			src, err := createDOM(v, pre...)
+			if err != nil {
+				return err
+			}
+			dom = append(dom, src...)
+			continue
+		}
+		s := &statics{prefix: "static" + v.StructName}
+		e, err := renderNode("Render", v.Recv, v.StructName, v.Node, m, s, pre...)
 		if err != nil {
 			return err
 		}
+		if len(s.specs) > 0 {
+			d := &ast.GenDecl{Tok: token.VAR, Specs: s.specs}
+			if len(s.specs) > 1 {
+				d.Lparen = 1
+			}
+			file.Decls = append(file.Decls, d)
+		}
 		file.Decls = append(file.Decls, e)
 	}
-	return format.Node(w, token.NewFileSet(), file)
+	var types []string
+	for k := range decoders {
+		types = append(types, k)
+	}
+	sort.Strings(types)
+	for _, k := range types {
+		file.Decls = append(file.Decls, decodeFunc(decoders[k]))
+	}
+	if dom == nil {
+		return format.Node(w, token.NewFileSet(), file)
+	}
+	// Create methods are generated as source, they are formatted together
+	// with the rest of the file.
+	var buf bytes.Buffer
+	if err := format.Node(&buf, token.NewFileSet(), file); err != nil {
+		return err
+	}
+	buf.Write(dom)
+	b, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// domImports returns the import of the dom package if a component in ctx is
+// compiled in dom mode.
+func domImports(ctx ...GeneratorContext) []ast.Spec {
+	for _, c := range ctx {
+		if c.DOM {
+			return []ast.Spec{importSpec(domImport)}
+		}
+	}
+	return nil
+}
+
+// componentMap adds to m the mapping of lower cased component names to their
+// struct names. Html tags are case insensitive so this is what we use to find
+// components used in templates.
+func componentMap(m map[string]string, ctx ...GeneratorContext) map[string]string {
+	if m == nil {
+		m = make(map[string]string)
+	}
+	for _, c := range ctx {
+		k := strings.ToLower(c.StructName)
+		if _, ok := m[k]; !ok {
+			m[k] = c.StructName
+		}
+	}
+	return m
+}
+
+func componentName(m map[string]string, tag string) string {
+	if n := m[tag]; n != "" {
+		return n
+	}
+	return tag
 }
 
 func importSpec(pkg string) *ast.ImportSpec {
@@ -229,8 +322,73 @@ func aliasVar(alias, pkg, selector string) *ast.ValueSpec {
 	}
 }
 
-func renderNode(name, recv, typ string, node *node.Node, m map[string]string) (*ast.FuncDecl, error) {
-	e, err := h(m, node)
+// statics collects package level declarations of static subtrees hoisted from
+// a Render method.
+type statics struct {
+	prefix string
+	specs  []ast.Spec
+}
+
+// add declares a package level variable holding the static node built by e
+// and returns its name.
+func (s *statics) add(e ast.Expr) *ast.Ident {
+	name := fmt.Sprintf("%s%d", s.prefix, len(s.specs))
+	s.specs = append(s.specs, &ast.ValueSpec{
+		Names: []*ast.Ident{{Name: name}},
+		Values: []ast.Expr{
+			&ast.CallExpr{
+				Fun:  &ast.Ident{Name: newStatic},
+				Args: []ast.Expr{e},
+			},
+		},
+	})
+	return &ast.Ident{Name: name}
+}
+
+// isStatic returns true if nd is an element whose subtree has no expressions,
+// components or directives, such subtrees render the same every time.
+func isStatic(nd *node.Node) bool {
+	if nd.Type != node.ElementNode {
+		return false
+	}
+	static := true
+	walkNodes(nd, func(n *node.Node) error {
+		switch {
+		case isComponent(n), hasDirective(n):
+			static = false
+		case n.Type == node.TextNode:
+			static = static && plain(n.Data)
+		}
+		for _, a := range n.Attr {
+			if a.Key == node.SpreadKey || strings.HasPrefix(a.Key, node.ClassPrefix) {
+				static = false
+			}
+			if v, ok := a.Val.(string); ok {
+				static = static && plain(v)
+			}
+		}
+		return nil
+	})
+	return static
+}
+
+// plain returns true if src has no expressions.
+func plain(src string) bool {
+	parts, err := expr.ExtractExpressions(src, '{', '}')
+	if err != nil {
+		return false
+	}
+	for _, p := range parts {
+		if !p.Plain {
+			return false
+		}
+	}
+	return true
+}
+
+func renderNode(name, recv, typ string, node *node.Node, m map[string]string, s *statics, pre ...ast.Stmt) (*ast.FuncDecl, error) {
+	// The root is never hoisted, every call returns a node of its own.
+	e, err := buildNode(m, node, s)
 	if err != nil {
 		return nil, err
 	}
@@ -322,11 +480,9 @@ func renderNode(name, recv, typ string, node *node.Node, m map[string]string) (*
 			},
 		},
 		Body: &ast.BlockStmt{
-			List: []ast.Stmt{
-				&ast.ReturnStmt{
-					Results: []ast.Expr{e},
-				},
-			},
+			List: append(pre, &ast.ReturnStmt{
+				Results: []ast.Expr{e},
+			}),
 		},
 	}, nil
 }
@@ -365,26 +521,69 @@ func hat(expr ...ast.Expr) ast.Expr {
 }
 
 func renderNodeType(m map[string]string, nd *node.Node) ast.Expr {
-	if elements.Valid(nd.Data) {
+	if !isComponent(nd) {
 		return &ast.BasicLit{
 			Kind:  token.INT,
 			Value: strconv.Itoa(int(nd.Type.(node.NodeType))),
 		}
 	}
-	n := m[nd.Data]
-	if n == "" {
-		n = nd.Data
+	name := componentName(m, nd.Data)
+	if i := strings.Index(name, "."); i != -1 {
+		return &ast.CompositeLit{Type: &ast.SelectorExpr{
+			X:   &ast.Ident{Name: name[:i]},
+			Sel: &ast.Ident{Name: name[i+1:]},
+		}}
 	}
-	return &ast.CompositeLit{Type: &ast.Ident{Name: n}}
+	return &ast.CompositeLit{Type: &ast.Ident{Name: name}}
 }
 
-func h(m map[string]string, nd *node.Node) (*ast.CallExpr, error) {
-	args := []ast.Expr{
-		renderNodeType(m, nd),
-		&ast.BasicLit{
+// h returns the expression that builds nd. Static subtrees are hoisted to
+// package level variables when s is not nil.
+func h(m map[string]string, nd *node.Node, s *statics) (ast.Expr, error) {
+	if s != nil && isStatic(nd) {
+		e, err := h(m, nd, nil)
+		if err != nil {
+			return nil, err
+		}
+		return s.add(e), nil
+	}
+	return buildNode(m, nd, s)
+}
+
+// buildNode returns the expression that builds nd itself, static subtrees
+// below it are hoisted when s is not nil.
+func buildNode(m map[string]string, nd *node.Node, s *statics) (ast.Expr, error) {
+	nd, applied, err := applyDirectives(nd)
+	if err != nil {
+		return nil, err
+	}
+	if len(applied) > 0 {
+		// Nothing is hoisted from elements with directives, their hooks must
+		// wrap nodes built on every render rather than shared ones.
+		e, err := h(m, nd, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range applied {
+			if v.d.Expr == nil {
+				continue
+			}
+			e, err = v.d.Expr(nd, v.attr, e)
+			if err != nil {
+				return nil, fmt.Errorf("<%s %s>: %v", nd.Data, v.attr.Key, err)
+			}
+		}
+		return e, nil
+	}
+	fun := newNode
+	args := []ast.Expr{renderNodeType(m, nd)}
+	if isComponent(nd) {
+		fun = newComponent
+	} else {
+		args = append(args, &ast.BasicLit{
 			Kind:  token.STRING,
 			Value: strconv.Quote(nd.Namespace),
-		},
+		})
 	}
 	if nd.Type == node.TextNode {
 		e, err := interpretText(nd.Data)
@@ -404,11 +603,9 @@ func h(m map[string]string, nd *node.Node) (*ast.CallExpr, error) {
 	}
 	var attrs []ast.Expr
 	for _, v := range nd.Attr {
-		txt, err := interpret(v.Val)
-		if err != nil {
-			return nil, err
-		}
-		e, err := parser.ParseExpr(txt)
+		// Values are passed as they are, this allows props to be of any type
+		// and lets node.New drop attributes whose values are nil.
+		e, err := interpretValue(v.Val)
 		if err != nil {
 			return nil, err
 		}
@@ -419,7 +616,7 @@ func h(m map[string]string, nd *node.Node) (*ast.CallExpr, error) {
 	args = append(args, hat(attrs...))
 	if len(nd.Children) > 0 {
 		for _, v := range nd.Children {
-			e, err := h(m, v)
+			e, err := h(m, v, s)
 			if err != nil {
 				return nil, err
 			}
@@ -428,7 +625,7 @@ func h(m map[string]string, nd *node.Node) (*ast.CallExpr, error) {
 	}
 	return &ast.CallExpr{
 		Fun: &ast.Ident{
-			Name: newNode,
+			Name: fun,
 		},
 		Args: args,
 	}, nil
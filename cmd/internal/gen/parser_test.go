@@ -2,8 +2,19 @@ package gen
 
 import (
 	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/gernest/greact/node"
 )
 
 func TestClear(t *testing.T) {
@@ -41,8 +52,16 @@ func TestInterpret(t *testing.T) {
 	sample := []struct {
 		expr, expect string
 	}{
-		{`{"hello"}`, `"hello"`},
-		{"{props.class}", "props.class"},
+		{`{"hello"}`, `expr.Eval(func() interface{} {
+	return "hello"
+})`},
+		{"{props.class}", `expr.Eval(func() interface{} {
+	return props.class
+})`},
+		{`{[]string{"a"}}`, `expr.Eval(func() interface{} {
+	return []string{"a"}
+})`},
+		{"{{literal}}", `expr.Eval("{literal}")`},
 	}
 	for _, v := range sample {
 		got, err := interpret(v.expr)
@@ -62,7 +81,7 @@ func TestInterpretText(t *testing.T) {
 		if err != nil {
 			ts.Fatal(err)
 		}
-		expect := `fmt.Sprint("hello")`
+		expect := `expr.Eval("hello")`
 		if v != expect {
 			ts.Errorf("expected %s got %s", expect, v)
 		}
@@ -72,12 +91,18 @@ func TestInterpretText(t *testing.T) {
 			src, expect string
 		}{
 			{
-				src:    `hello, {props.String("name")}`,
-				expect: `fmt.Sprint("hello,", props.String("name"))`,
+				src: `hello, {props.String("name")}`,
+				expect: `expr.Eval("hello,", func() interface{} {
+	return props.String("name")
+})`,
 			},
 			{
-				src:    `{props.String("initialName")}/{s.State().String("name")}`,
-				expect: `fmt.Sprint(props.String("initialName"), "/", s.State().String("name"))`,
+				src: `{props.String("initialName")}/{s.State().String("name")}`,
+				expect: `expr.Eval(func() interface{} {
+	return props.String("initialName")
+}, "/", func() interface{} {
+	return s.State().String("name")
+})`,
 			},
 		}
 		for _, v := range sample {
@@ -92,9 +117,13 @@ func TestInterpretText(t *testing.T) {
 	})
 }
 
+var update = flag.Bool("update", false, "update golden files of generate tests")
+
 func TestGenerate(t *testing.T) {
 	geneateTest(t, "fixture/generate/basic.html")
 	geneateTest(t, "fixture/generate/custom.html")
+	geneateTest(t, "fixture/generate/spread.html")
+	geneateTest(t, "fixture/generate/static.html")
 	geneateTest(t, "fixture/generate/custom_with_mapping.html", map[string]string{
 		"custom": "Custom",
 	})
@@ -126,7 +155,11 @@ func geneateTest(t *testing.T, file string, m ...map[string]string) {
 			t.Fatal(err)
 		}
 		output := file + ".go.out"
-		ioutil.WriteFile(output, buf.Bytes(), 0600)
+		if *update {
+			if err := ioutil.WriteFile(output, buf.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
 		o, err := ioutil.ReadFile(output)
 		if err != nil {
 			t.Fatal(err)
@@ -136,3 +169,249 @@ func geneateTest(t *testing.T, file string, m ...map[string]string) {
 		}
 	})
 }
+
+func TestHoistStatic(t *testing.T) {
+	n, err := ParseString(`<div>
+	<header><h1>Title</h1></header>
+	<main>{props["body"].Val}</main>
+	<footer class="bottom"><a href="/about">About</a></footer>
+	<p class:active={true}>text</p>
+</div>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &statics{prefix: "static"}
+	e, err := h(nil, n, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hoisted []string
+	for _, spec := range s.specs {
+		call := spec.(*ast.ValueSpec).Values[0].(*ast.CallExpr)
+		if fn := call.Fun.(*ast.Ident).Name; fn != newStatic {
+			t.Errorf("expected hoisted nodes to be built with %s got %s", newStatic, fn)
+		}
+		el := call.Args[0].(*ast.CallExpr).Args[2].(*ast.BasicLit).Value
+		hoisted = append(hoisted, el)
+	}
+	expect := []string{`"header"`, `"footer"`}
+	if !reflect.DeepEqual(hoisted, expect) {
+		t.Errorf("expected %v to be hoisted got %v", expect, hoisted)
+	}
+	var refs []string
+	ast.Inspect(e, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && strings.HasPrefix(id.Name, "static") {
+			refs = append(refs, id.Name)
+		}
+		return true
+	})
+	expectRefs := []string{"static0", "static1"}
+	if !reflect.DeepEqual(refs, expectRefs) {
+		t.Errorf("expected render to use %v got %v", expectRefs, refs)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	tpl := `<div>
+	<p title="{h.Name}">{h.Name}</p>
+	<Card title={h.Name} {...h.Attrs}></Card>
+	<p disabled {...h.Attrs}>{{h.Name}} &amp; {h.Name}</p>
+	<Card label="text" title={h.Name}></Card>
+</div>`
+	n, err := ParseString(tpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := &GeneratorContext{
+		StructName: "Hello",
+		Node:       n,
+		Template:   tpl,
+		Pos:        token.Position{Filename: "hello.html", Line: 1, Column: 1},
+	}
+	o, err := collectOrigins(ctx, map[string]string{"card": "Card"}, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	locate(ctx, o)
+	var got []string
+	for _, v := range o {
+		got = append(got, fmt.Sprintf("%d:%d %s", v.position().Line, v.position().Column, v.describe()))
+	}
+	expect := []string{
+		"2:5 title={h.Name}",
+		"2:22 {h.Name}",
+		"3:2 <Card>",
+		"3:8 title={h.Name}",
+		"3:23 {...h.Attrs}",
+		"4:14 {...h.Attrs}",
+		"4:44 {h.Name}",
+		"5:2 <Card>",
+		`5:8 label="text"`,
+		"5:21 title={h.Name}",
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(expect, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestTypeCheck(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		_, err := generateDir(t, "fixture/check/valid")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := generateDir(t, "fixture/check/invalid")
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		expect := []string{
+			"fixture/check/invalid/invalid.go:12:38: Hello: {h.Nmae}: h.Nmae undefined (type *Hello has no field or method Nmae)",
+			"fixture/check/invalid/invalid.go:13:2: Hello: <missing>: undefined: missing",
+		}
+		got := strings.Split(err.Error(), "\n")
+		if !reflect.DeepEqual(got, expect) {
+			t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(expect, "\n"), err)
+		}
+	})
+	t.Run("attribute types", func(t *testing.T) {
+		_, err := generateDir(t, "fixture/check/attrs")
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		expect := []string{
+			"fixture/check/attrs/attrs.go:11:13: Hello: onclick={h.Name}: onclick must be a func, got string",
+			"fixture/check/attrs/attrs.go:11:30: Hello: hidden={h.Name}: hidden must be a bool, got string",
+			"fixture/check/attrs/attrs.go:12:4: Hello: class:active={h.Name}: class:active must be a bool, got string",
+			"fixture/check/attrs/attrs.go:12:26: Hello: {...h.Name}: cannot spread string, expected []node.Attribute, greact.Props, map[string]interface{} or map[string]string",
+			"fixture/check/attrs/attrs.go:13:4: Hello: class={1}: class must be a string, []string, map[string]bool or node.ClassList, got int",
+			"fixture/check/attrs/attrs.go:13:14: Hello: style={h.Name == \"\"}: style must be a string, map or struct of css properties, got bool",
+		}
+		got := strings.Split(err.Error(), "\n")
+		if !reflect.DeepEqual(got, expect) {
+			t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(expect, "\n"), err)
+		}
+	})
+}
+
+func TestTypeCheckGenerated(t *testing.T) {
+	dir := "fixture/check/valid"
+	fs := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fs, dir, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := pkgs["valid"]
+	c, err := discoverComponents(fs, dir, pkg, warnf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := componentMap(nil, c...)
+	var buf bytes.Buffer
+	if err := Generate(&buf, pkg.Name, m, c...); err != nil {
+		t.Fatal(err)
+	}
+	// Generated code that doesn't compile fails generation, errors point to
+	// the template when they can.
+	src := strings.Replace(buf.String(), "import (", "import (\n\t\"strings\"", 1)
+	src = strings.Replace(src, "h.Selected", "h.Selected()", 1)
+	src = strings.Replace(src, "return h.Name", "return h.Nmae", 1)
+	err = typeCheck(fs, dir, pkg, []byte(src), m, c)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	expect := []string{
+		"fixture/check/valid/valid.go:17:53: Hello: class:selected={h.Selected}: invalid operation: cannot call h.Selected (variable of type bool): bool is not a function (generated valid_render_gen.go:19:153)",
+		"fixture/check/valid/valid.go:17:10: Hello: h.Nmae undefined (type *Hello has no field or method Nmae) (generated valid_render_gen.go:20:12)",
+		`fixture/check/valid/valid_render_gen.go:4:2: "strings" imported and not used`,
+	}
+	got := strings.Split(err.Error(), "\n")
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(expect, "\n"), err)
+	}
+}
+
+func generateDir(t *testing.T, dir string) ([]byte, error) {
+	t.Helper()
+	fs := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fs, dir, func(i os.FileInfo) bool {
+		return !strings.HasSuffix(i.Name(), "_render_gen.go")
+	}, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pkg := range pkgs {
+		return generatePackage(fs, dir, pkg)
+	}
+	t.Fatalf("no package in %s", dir)
+	return nil, nil
+}
+
+func TestParseSpread(t *testing.T) {
+	n, err := ParseString(`<input type="text" {...props.Attrs} value={strings.Join(a, " ")}>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []node.Attribute{
+		{Key: "type", Val: "text"},
+		{Key: node.SpreadKey, Val: "{props.Attrs}"},
+		{Key: "value", Val: `{strings.Join(a, " ")}`},
+	}
+	if !reflect.DeepEqual(n.Attr, expect) {
+		t.Errorf("expected %v got %v", expect, n.Attr)
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	saved := directives.list
+	directives.list = nil
+	defer func() {
+		directives.list = saved
+	}()
+	tooltip := Directive{
+		Prefix: "g-tooltip",
+		Usage:  "sets the title of an element",
+		Node: func(nd *node.Node, attr node.Attribute) error {
+			nd.Attr = append(nd.Attr, node.Attribute{Key: "title", Val: attr.Val})
+			return nil
+		},
+	}
+	track := Directive{
+		Prefix: "g-track",
+		Usage:  "tracks events of an element",
+		Expr: func(nd *node.Node, attr node.Attribute, e ast.Expr) (ast.Expr, error) {
+			v, err := ValueExpr(attr.Val)
+			if err != nil {
+				return nil, err
+			}
+			return &ast.CallExpr{
+				Fun: &ast.Ident{Name: "track"},
+				Args: []ast.Expr{
+					&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(strings.TrimPrefix(attr.Key, "g-track:"))},
+					v, e,
+				},
+			}, nil
+		},
+	}
+	for _, d := range []Directive{tooltip, track} {
+		if err := RegisterDirective(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	invalid := []Directive{tooltip, {Prefix: "g-Upper", Node: tooltip.Node}, {Prefix: "g-none"}, {}}
+	for _, d := range invalid {
+		if err := RegisterDirective(d); err == nil {
+			t.Errorf("expected an error registering %q", d.Prefix)
+		}
+	}
+	var buf bytes.Buffer
+	if err := listDirectives(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expect := "g-tooltip  sets the title of an element\ng-track    tracks events of an element\n"
+	if buf.String() != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, buf.String())
+	}
+	geneateTest(t, "fixture/generate/directive.html")
+}
@@ -0,0 +1,212 @@
+package gen
+
+import (
+	"bytes"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/gernest/greact/expr"
+)
+
+// PositionMapper maps positions in generated render files to the templates
+// they were generated from. Files and their packages are parsed once, so a
+// mapper should live as long as the files don't change, like while mapping the
+// output of one build.
+type PositionMapper struct {
+	files map[string]*mappedFile
+}
+
+// mappedFile is a parsed render file with the components of its package.
+type mappedFile struct {
+	fs   *token.FileSet
+	file *ast.File
+
+	// components maps names of components to their context and to the
+	// expressions of their templates.
+	components map[string]*GeneratorContext
+	origins    map[string][]origin
+}
+
+// NewPositionMapper returns an empty PositionMapper.
+func NewPositionMapper() *PositionMapper {
+	return &PositionMapper{files: make(map[string]*mappedFile)}
+}
+
+// Position maps pos in a generated render file to the template expression the
+// code at pos was generated from, or to the start of the template when there
+// is no such expression. The returned string is the component name, false is
+// returned when pos is not in a component method.
+func (m *PositionMapper) Position(pos token.Position) (token.Position, string, bool) {
+	if !strings.HasSuffix(pos.Filename, "_render_gen.go") {
+		return token.Position{}, "", false
+	}
+	f, ok := m.files[pos.Filename]
+	if !ok {
+		f = parseMappedFile(pos.Filename)
+		m.files[pos.Filename] = f
+	}
+	if f == nil {
+		return token.Position{}, "", false
+	}
+	tf := f.fs.File(f.file.Pos())
+	if pos.Line < 1 || pos.Line > tf.LineCount() {
+		return token.Position{}, "", false
+	}
+	p := tf.LineStart(pos.Line)
+	if pos.Column > 1 {
+		p += token.Pos(pos.Column - 1)
+	}
+	ctx, o := f.find(p)
+	switch {
+	case o != nil:
+		return o.position(), ctx.StructName, true
+	case ctx != nil:
+		return ctx.Pos, ctx.StructName, true
+	}
+	return token.Position{}, "", false
+}
+
+// parseMappedFile parses the render file at path and the components of its
+// package, nil is returned when either fails.
+func parseMappedFile(path string) *mappedFile {
+	fs := token.NewFileSet()
+	file, err := parser.ParseFile(fs, path, nil, 0)
+	if err != nil {
+		return nil
+	}
+	dir := filepath.Dir(path)
+	pkg, err := parsePackage(&build.Default, fs, dir)
+	if err != nil || pkg == nil {
+		return nil
+	}
+	ignore := func(string, ...interface{}) {}
+	c, err := discoverComponents(fs, dir, pkg, ignore)
+	if err != nil {
+		return nil
+	}
+	m := componentMap(nil, c...)
+	var origins []origin
+	for i := range c {
+		o, err := collectOrigins(&c[i], m, c[i].Node)
+		if err != nil {
+			continue
+		}
+		locate(&c[i], o)
+		origins = append(origins, o...)
+	}
+	return newMappedFile(fs, file, c, origins)
+}
+
+// newMappedFile returns the mapping of file, generated for components ctx, to
+// their templates. origins are the located expressions of the templates.
+func newMappedFile(fs *token.FileSet, file *ast.File, ctx []GeneratorContext, origins []origin) *mappedFile {
+	f := &mappedFile{
+		fs:         fs,
+		file:       file,
+		components: make(map[string]*GeneratorContext),
+		origins:    make(map[string][]origin),
+	}
+	for i := range ctx {
+		f.components[ctx[i].StructName] = &ctx[i]
+	}
+	for _, o := range origins {
+		f.origins[o.ctx.StructName] = append(f.origins[o.ctx.StructName], o)
+	}
+	return f
+}
+
+// find returns the component whose method contains p and the origin of the
+// template expression the code at p was generated from. The origin is nil when
+// there is no such expression and both are nil when p is not in a component
+// method.
+func (f *mappedFile) find(p token.Pos) (*GeneratorContext, *origin) {
+	for _, d := range f.file.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Body == nil {
+			continue
+		}
+		if p < fn.Pos() || p > fn.End() {
+			continue
+		}
+		typ := fn.Recv.List[0].Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		id, ok := typ.(*ast.Ident)
+		if !ok {
+			break
+		}
+		ctx, ok := f.components[id.Name]
+		if !ok {
+			break
+		}
+		if o, ok := f.match(fn, p, f.origins[id.Name]); ok {
+			return ctx, &o
+		}
+		return ctx, nil
+	}
+	return nil, nil
+}
+
+// match returns the origin of the innermost expression of fn around p. Code of
+// expressions is compared in its printed form, when the same code is used more
+// than once the occurrences are matched in order.
+func (f *mappedFile) match(fn *ast.FuncDecl, p token.Pos, origins []origin) (origin, bool) {
+	code := make(map[string][]origin)
+	for _, o := range origins {
+		if o.plain || o.component != "" {
+			continue
+		}
+		// expressions are generated as they are, statements are wrapped in a
+		// func literal.
+		e, err := parser.ParseExpr(o.text)
+		if err != nil {
+			if e, err = expr.Parse(o.text); err != nil {
+				continue
+			}
+		}
+		s := printExpr(e)
+		code[s] = append(code[s], o)
+	}
+	var path []ast.Expr
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if n == nil || p < n.Pos() || p >= n.End() {
+			return false
+		}
+		if e, ok := n.(ast.Expr); ok {
+			path = append(path, e)
+		}
+		return true
+	})
+	for i := len(path) - 1; i >= 0; i-- {
+		s := printExpr(path[i])
+		o := code[s]
+		if len(o) == 0 {
+			continue
+		}
+		// the number of occurrences of the same code before this one.
+		n := 0
+		ast.Inspect(fn.Body, func(x ast.Node) bool {
+			if e, ok := x.(ast.Expr); ok && x.Pos() < path[i].Pos() && printExpr(e) == s {
+				n++
+			}
+			return true
+		})
+		if n >= len(o) {
+			n = 0
+		}
+		return o[n], true
+	}
+	return origin{}, false
+}
+
+func printExpr(e ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, token.NewFileSet(), e)
+	return buf.String()
+}
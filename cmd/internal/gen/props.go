@@ -0,0 +1,347 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// propsField is the name of the component field that holds typed props.
+const propsField = "Props"
+
+// TypedProps describes a struct a component uses to read its props.
+//
+// Fields are mapped to props by the greact struct tag, or the lower cased
+// field name when there is no tag. Fields tagged with greact:"-" are ignored.
+// A default value can be given with the default tag, it is used when the prop
+// is not set.
+//
+//	type ButtonProps struct {
+//		Label    string `greact:"label" default:"Click"`
+//		Disabled bool
+//	}
+type TypedProps struct {
+	// Type is the name of the struct.
+	Type   string
+	Fields []PropField
+
+	// Imports maps names to import paths of packages the field types refer
+	// to, the generated decoder imports them.
+	Imports map[string]string
+}
+
+// PropField is a field of TypedProps.
+type PropField struct {
+	// Name is the name of the field and Key is the name of the prop it is read
+	// from.
+	Name, Key string
+
+	// Type is the go type of the field.
+	Type string
+
+	// Default is the go expression of the default value, it is empty when the
+	// default is the zero value.
+	Default string
+}
+
+// decodeName returns the name of the generated function which populates the
+// struct from greact.Props.
+func (t *TypedProps) decodeName() string {
+	return "decode" + strings.ToUpper(t.Type[:1]) + t.Type[1:]
+}
+
+// propsKey returns the prop key of a struct field with the given name and tag,
+// ok is false if the field is ignored.
+func propsKey(name, tag string) (key string, ok bool) {
+	key = reflect.StructTag(tag).Get("greact")
+	if i := strings.Index(key, ","); i != -1 {
+		key = key[:i]
+	}
+	switch key {
+	case "-":
+		return "", false
+	case "":
+		key = strings.ToLower(name)
+	}
+	return key, true
+}
+
+// structTypes returns struct types declared in files keyed by their names.
+func structTypes(files []*ast.File) map[string]*ast.TypeSpec {
+	m := make(map[string]*ast.TypeSpec)
+	for _, f := range files {
+		for _, d := range f.Decls {
+			g, ok := d.(*ast.GenDecl)
+			if !ok || g.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range g.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if _, ok := ts.Type.(*ast.StructType); ok {
+					m[ts.Name.Name] = ts
+				}
+			}
+		}
+	}
+	return m
+}
+
+// typedProps returns the props struct of component st. This returns nil when
+// the component has no Props field whose type is a struct declared in the same
+// package. Packages of qualified field types are looked up in the imports of
+// files.
+func typedProps(fs *token.FileSet, st *ast.StructType, structs map[string]*ast.TypeSpec, files []*ast.File) (*TypedProps, error) {
+	for _, f := range st.Fields.List {
+		if len(f.Names) != 1 || f.Names[0].Name != propsField {
+			continue
+		}
+		id, ok := f.Type.(*ast.Ident)
+		if !ok {
+			return nil, nil
+		}
+		ts, ok := structs[id.Name]
+		if !ok {
+			return nil, nil
+		}
+		t := &TypedProps{Type: id.Name}
+		for _, field := range ts.Type.(*ast.StructType).Fields.List {
+			var tag string
+			if field.Tag != nil {
+				tag, _ = strconv.Unquote(field.Tag.Value)
+			}
+			typ := typeString(field.Type)
+			if err := t.addImports(field.Type, files); err != nil {
+				return nil, fmt.Errorf("%s: %s: %v", fs.Position(field.Type.Pos()), id.Name, err)
+			}
+			for _, n := range field.Names {
+				if !n.IsExported() {
+					continue
+				}
+				key, ok := propsKey(n.Name, tag)
+				if !ok {
+					continue
+				}
+				p := PropField{Name: n.Name, Key: key, Type: typ}
+				if v, ok := reflect.StructTag(tag).Lookup("default"); ok {
+					d, err := defaultValue(field.Type, v)
+					if err != nil {
+						return nil, fmt.Errorf("%s: default value of %s.%s: %v",
+							fs.Position(field.Pos()), id.Name, n.Name, err)
+					}
+					p.Default = d
+				}
+				t.Fields = append(t.Fields, p)
+			}
+		}
+		return t, nil
+	}
+	return nil, nil
+}
+
+// addImports adds to t.Imports the packages that qualify names in typ.
+func (t *TypedProps) addImports(typ ast.Expr, files []*ast.File) error {
+	var err error
+	var imports map[string]string
+	ast.Inspect(typ, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || err != nil {
+			return err == nil
+		}
+		id, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if imports == nil {
+			if imports, err = packageImports(files); err != nil {
+				return false
+			}
+		}
+		p, ok := imports[id.Name]
+		if !ok {
+			err = fmt.Errorf("no import for %s", id.Name)
+			return false
+		}
+		if r, ok := reservedImports[id.Name]; ok && r != p {
+			err = fmt.Errorf("%s is reserved for generated code, import %s under a different name", id.Name, p)
+			return false
+		}
+		if t.Imports == nil {
+			t.Imports = make(map[string]string)
+		}
+		t.Imports[id.Name] = p
+		return false
+	})
+	return err
+}
+
+func typeString(e ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, token.NewFileSet(), e)
+	return buf.String()
+}
+
+// defaultValue returns go expression of the default value v for a field of
+// type typ. Only fields of basic types can have default values.
+func defaultValue(typ ast.Expr, v string) (string, error) {
+	id, ok := typ.(*ast.Ident)
+	if !ok {
+		return "", fmt.Errorf("defaults are not supported for %s", typeString(typ))
+	}
+	switch id.Name {
+	case "string":
+		return strconv.Quote(v), nil
+	case "bool":
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(b), nil
+	case "int", "int8", "int16", "int32", "int64":
+		bits := map[string]int{"int": 0, "int8": 8, "int16": 16, "int32": 32, "int64": 64}
+		i, err := strconv.ParseInt(v, 10, bits[id.Name])
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(i, 10), nil
+	case "uint", "uint8", "uint16", "uint32", "uint64", "byte":
+		bits := map[string]int{"uint": 0, "uint8": 8, "byte": 8, "uint16": 16, "uint32": 32, "uint64": 64}
+		i, err := strconv.ParseUint(v, 10, bits[id.Name])
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatUint(i, 10), nil
+	case "float32", "float64":
+		bits := 64
+		if id.Name == "float32" {
+			bits = 32
+		}
+		f, err := strconv.ParseFloat(v, bits)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(f, 'g', -1, bits), nil
+	}
+	return "", fmt.Errorf("defaults are not supported for %s", id.Name)
+}
+
+// decodeStmt returns the statement that populates the props field of the
+// component from greact.Props.
+//
+//	recv.Props = decodeButtonProps(props)
+func decodeStmt(recv string, t *TypedProps) ast.Stmt {
+	return &ast.AssignStmt{
+		Lhs: []ast.Expr{
+			&ast.SelectorExpr{
+				X:   &ast.Ident{Name: recv},
+				Sel: &ast.Ident{Name: propsField},
+			},
+		},
+		Tok: token.ASSIGN,
+		Rhs: []ast.Expr{
+			&ast.CallExpr{
+				Fun:  &ast.Ident{Name: t.decodeName()},
+				Args: []ast.Expr{&ast.Ident{Name: "props"}},
+			},
+		},
+	}
+}
+
+// decodeFunc returns a function that converts greact.Props to t.
+//
+//	func decodeButtonProps(props greact.Props) ButtonProps {
+//		p := ButtonProps{Label: "Click"}
+//		if v, ok := props["label"].Val.(string); ok {
+//			p.Label = v
+//		}
+//		return p
+//	}
+func decodeFunc(t *TypedProps) *ast.FuncDecl {
+	lit := &ast.CompositeLit{Type: &ast.Ident{Name: t.Type}}
+	for _, f := range t.Fields {
+		if f.Default == "" {
+			continue
+		}
+		v, _ := parser.ParseExpr(f.Default)
+		lit.Elts = append(lit.Elts, &ast.KeyValueExpr{
+			Key:   &ast.Ident{Name: f.Name},
+			Value: v,
+		})
+	}
+	body := []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: []ast.Expr{&ast.Ident{Name: "p"}},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{lit},
+		},
+	}
+	for _, f := range t.Fields {
+		typ, _ := parser.ParseExpr(f.Type)
+		body = append(body, &ast.IfStmt{
+			Init: &ast.AssignStmt{
+				Lhs: []ast.Expr{&ast.Ident{Name: "v"}, &ast.Ident{Name: "ok"}},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{
+					&ast.TypeAssertExpr{
+						X: &ast.SelectorExpr{
+							X: &ast.IndexExpr{
+								X: &ast.Ident{Name: "props"},
+								Index: &ast.BasicLit{
+									Kind:  token.STRING,
+									Value: strconv.Quote(f.Key),
+								},
+							},
+							Sel: &ast.Ident{Name: "Val"},
+						},
+						Type: typ,
+					},
+				},
+			},
+			Cond: &ast.Ident{Name: "ok"},
+			Body: &ast.BlockStmt{
+				List: []ast.Stmt{
+					&ast.AssignStmt{
+						Lhs: []ast.Expr{
+							&ast.SelectorExpr{
+								X:   &ast.Ident{Name: "p"},
+								Sel: &ast.Ident{Name: f.Name},
+							},
+						},
+						Tok: token.ASSIGN,
+						Rhs: []ast.Expr{&ast.Ident{Name: "v"}},
+					},
+				},
+			},
+		})
+	}
+	body = append(body, &ast.ReturnStmt{
+		Results: []ast.Expr{&ast.Ident{Name: "p"}},
+	})
+	return &ast.FuncDecl{
+		Name: &ast.Ident{Name: t.decodeName()},
+		Type: &ast.FuncType{
+			Params: &ast.FieldList{
+				List: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "props"}},
+						Type: &ast.SelectorExpr{
+							X:   &ast.Ident{Name: packageName},
+							Sel: &ast.Ident{Name: "Props"},
+						},
+					},
+				},
+			},
+			Results: &ast.FieldList{
+				List: []*ast.Field{
+					{Type: &ast.Ident{Name: t.Type}},
+				},
+			},
+		},
+		Body: &ast.BlockStmt{List: body},
+	}
+}
@@ -2,16 +2,23 @@ package gen
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"go/ast"
+	"go/build"
 	"go/parser"
 	"go/token"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
+	"github.com/gernest/greact/cmd/internal/watch"
 	"github.com/urfave/cli"
 )
 
@@ -19,70 +26,737 @@ import (
 // components.
 func RenderCMD() cli.Command {
 	return cli.Command{
-		Name:   "render",
-		Usage:  "generates Render functions for components",
+		Name:      "render",
+		Usage:     "generates Render functions for components",
+		ArgsUsage: "[packages]",
+		Description: `Arguments can be directories, go files or patterns ending with /... which
+   match the directory and all its sub directories. A go file selects the
+   package it belongs to. Directories named testdata or vendor and those
+   starting with . or _ are skipped by patterns.
+
+   When no argument is given the package in the current directory is used.
+
+   With --check nothing is written, instead a diff is printed for every
+   generated file that is out of date and the command fails. Packages whose
+   templates fail to parse or type check are reported as failures, not as out
+   of date.
+
+   With --watch the command keeps running and regenerates a package whenever
+   one of its go or template files changes, templates in other directories
+   included.
+
+   Components whose type is documented with //greact:dom get a Create method
+   instead of Render. It builds the DOM of the template once and returns a
+   function that only updates the nodes bound to expressions.`,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "tags",
+				Usage: "comma separated list of build tags to consider satisfied",
+			},
+			cli.BoolFlag{
+				Name:  "check",
+				Usage: "report generated files that are out of date instead of writing them",
+			},
+			cli.BoolFlag{
+				Name:  "watch",
+				Usage: "keep running and regenerate packages when their files change",
+			},
+			cli.DurationFlag{
+				Name:  "interval",
+				Usage: "how often files are checked for changes in watch mode",
+				Value: watch.DefaultInterval,
+			},
+		},
 		Action: render,
 	}
 }
 
-// render generates component's render functions from a given package.
+// render generates component's render functions from the given packages.
 func render(ctx *cli.Context) error {
-	path := ctx.Args().First()
-	info, err := os.Stat(path)
+	dirs, err := packageDirs(ctx.Args()...)
 	if err != nil {
 		return err
 	}
-	if info.IsDir() {
-		return renderDir(ctx)
+	b := buildContext(ctx)
+	check := ctx.Bool("check")
+	if ctx.Bool("watch") {
+		if check {
+			return errors.New("render: --check and --watch can not be used together")
+		}
+		watchDirs(b, dirs, ctx.Duration("interval"), nil)
+		return nil
+	}
+	return renderDirs(b, dirs, check, os.Stdout, os.Stderr)
+}
+
+// renderDirs generates render files of dirs or, with check, writes diffs of
+// those that are out of date to w. Errors are written to errw as they happen.
+// With check, packages that fail to generate are counted apart from those that
+// are out of date so broken templates are not reported as stale files.
+func renderDirs(b *build.Context, dirs []string, check bool, w, errw io.Writer) error {
+	failed, stale := 0, 0
+	for _, dir := range dirs {
+		var err error
+		if check {
+			err = checkDir(b, dir, w)
+		} else {
+			err = renderDir(b, dir)
+		}
+		if err == nil {
+			continue
+		}
+		fmt.Fprintln(errw, err)
+		if _, ok := err.(*staleError); ok {
+			stale++
+		} else {
+			failed++
+		}
+	}
+	var msg []string
+	if failed > 0 {
+		if check {
+			msg = append(msg, fmt.Sprintf("failed to check %d of %d packages", failed, len(dirs)))
+		} else {
+			msg = append(msg, fmt.Sprintf("failed to generate %d of %d packages", failed, len(dirs)))
+		}
+	}
+	if stale > 0 {
+		msg = append(msg, fmt.Sprintf("%d of %d packages are out of date", stale, len(dirs)))
+	}
+	if len(msg) > 0 {
+		return errors.New("render: " + strings.Join(msg, ", "))
+	}
+	return nil
+}
+
+// buildContext returns the build context used to select package files, it
+// respects the tags flag.
+func buildContext(ctx *cli.Context) *build.Context {
+	b := build.Default
+	if tags := ctx.String("tags"); tags != "" {
+		for _, t := range strings.Split(tags, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				b.BuildTags = append(b.BuildTags, t)
+			}
+		}
+	}
+	return &b
+}
+
+// packageDirs resolves args to a list of package directories. Each directory
+// appears only once and in the order it was first matched.
+func packageDirs(args ...string) ([]string, error) {
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	var dirs []string
+	seen := make(map[string]bool)
+	add := func(dir string) {
+		dir = filepath.Clean(dir)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, a := range args {
+		if a == "..." || strings.HasSuffix(a, "/...") {
+			root := strings.TrimSuffix(strings.TrimSuffix(a, "..."), "/")
+			if root == "" {
+				root = "."
+			}
+			err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if !info.IsDir() {
+					return nil
+				}
+				if path != root && skipDir(info.Name()) {
+					return filepath.SkipDir
+				}
+				add(path)
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		info, err := os.Stat(a)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			add(a)
+			continue
+		}
+		if filepath.Ext(a) != ".go" {
+			return nil, fmt.Errorf("%s is not a go file", a)
+		}
+		add(filepath.Dir(a))
+	}
+	return dirs, nil
+}
+
+// skipDir returns true for directories that are not matched by patterns.
+func skipDir(name string) bool {
+	switch name {
+	case "testdata", "vendor":
+		return true
 	}
-	return renderFile(ctx)
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// RenderPackage generates the render file of the package in dir. Files are
+// selected as if tags were build tags.
+func RenderPackage(dir string, tags ...string) error {
+	b := build.Default
+	b.BuildTags = append(b.BuildTags, tags...)
+	return renderDir(&b, dir)
 }
 
-func renderDir(ctx *cli.Context) error {
+// PackageDirs returns directories of packages selected by args, see the render
+// command for the arguments it accepts.
+func PackageDirs(args ...string) ([]string, error) {
+	return packageDirs(args...)
+}
+
+// IsRenderSource returns true if changes to the file at path affect generated
+// render files.
+func IsRenderSource(path string) bool {
+	return isRenderSource(path)
+}
+
+// TemplateDirs returns directories outside dir holding template files of the
+// package in dir, they need to be watched along with dir. Files are selected
+// as if tags were build tags.
+func TemplateDirs(dir string, tags ...string) ([]string, error) {
+	b := build.Default
+	b.BuildTags = append(b.BuildTags, tags...)
+	return templateDirs(&b, dir)
+}
+
+// renderDir generates render functions for the package in dir. Only files that
+// satisfy the build constraints of b are considered. Directories without go
+// files are ignored.
+func renderDir(b *build.Context, dir string) error {
 	fs := token.NewFileSet()
-	path := ctx.Args().First()
-	pkgs, err := parser.ParseDir(fs, path, func(i os.FileInfo) bool {
-		if strings.HasSuffix(i.Name(), "_test.go") {
-			return false
+	pkg, err := parsePackage(b, fs, dir)
+	if err != nil {
+		return err
+	}
+	if pkg == nil {
+		return nil
+	}
+	return processPackage(fs, dir, pkg)
+}
+
+// watchDirs renders all dirs and then keeps regenerating the ones whose files
+// change until stop is closed. Errors are printed and don't stop watching.
+func watchDirs(b *build.Context, dirs []string, interval time.Duration, stop <-chan struct{}) {
+	set := make(watchSet)
+	for _, dir := range dirs {
+		if err := renderDir(b, dir); err != nil {
+			fmt.Fprintln(os.Stderr, err)
 		}
-		return !strings.HasSuffix(i.Name(), "_render_gen.go")
-	}, 0)
+		set.update(b, dir)
+	}
+	w := watch.New(isRenderSource, set.dirs()...)
+	w.Interval = interval
+	fmt.Printf("render: watching %d packages for changes\n", len(dirs))
+	w.Run(stop, func(changed []string) {
+		seen := make(map[string]bool)
+		for _, f := range changed {
+			for _, dir := range set.packages(filepath.Dir(f)) {
+				if seen[dir] {
+					continue
+				}
+				seen[dir] = true
+				// The template directives might have changed.
+				set.update(b, dir)
+				if err := renderDir(b, dir); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					continue
+				}
+				fmt.Printf("render: regenerated %s\n", dir)
+			}
+		}
+		w.Dirs = set.dirs()
+	})
+}
+
+// watchSet maps package directories to the other directories holding their
+// templates.
+type watchSet map[string][]string
+
+// update sets the template directories of the package in dir. They are kept
+// when they can't be found, like when a template being edited doesn't parse,
+// so the fix is picked up.
+func (s watchSet) update(b *build.Context, dir string) {
+	dirs, err := templateDirs(b, dir)
+	if _, ok := s[dir]; ok && err != nil {
+		return
+	}
+	s[dir] = dirs
+}
+
+// dirs returns the sorted list of directories to watch.
+func (s watchSet) dirs() []string {
+	m := make(map[string]bool)
+	for pkg, dirs := range s {
+		m[pkg] = true
+		for _, dir := range dirs {
+			m[dir] = true
+		}
+	}
+	var dirs []string
+	for dir := range m {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// packages returns the sorted list of packages affected by changes to files
+// in dir.
+func (s watchSet) packages(dir string) []string {
+	var pkgs []string
+	for pkg, dirs := range s {
+		if pkg == dir {
+			pkgs = append(pkgs, pkg)
+			continue
+		}
+		for _, d := range dirs {
+			if d == dir {
+				pkgs = append(pkgs, pkg)
+				break
+			}
+		}
+	}
+	sort.Strings(pkgs)
+	return pkgs
+}
+
+// templateDirs returns directories, other than dir, of template files used by
+// components of the package in dir. Templates named like sub/x.html or
+// ../shared/x.html by the greact:template directive live in them.
+func templateDirs(b *build.Context, dir string) ([]string, error) {
+	fs := token.NewFileSet()
+	pkg, err := parsePackage(b, fs, dir)
+	if err != nil || pkg == nil {
+		return nil, err
+	}
+	ignore := func(string, ...interface{}) {}
+	c, err := discoverComponents(fs, dir, pkg, ignore)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, ctx := range c {
+		d := filepath.Dir(ctx.Pos.Filename)
+		if ctx.Pos.Filename == "" || d == filepath.Clean(dir) || seen[d] {
+			continue
+		}
+		seen[d] = true
+		dirs = append(dirs, d)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// isRenderSource returns true if path is a file that affects the output of
+// render functions.
+func isRenderSource(path string) bool {
+	switch filepath.Ext(path) {
+	case ".go":
+		return !strings.HasSuffix(path, "_test.go") &&
+			!strings.HasSuffix(path, "_render_gen.go")
+	case ".html", ".gohtml":
+		return true
+	}
+	return false
+}
+
+// checkDir compares render files generated for the package in dir with the
+// ones on disk. A unified diff is written to w for every file that differs,
+// including generated files that no longer have components to render, and
+// *staleError is returned. Other errors mean the package could not be
+// generated.
+func checkDir(b *build.Context, dir string, w io.Writer) error {
+	fs := token.NewFileSet()
+	pkg, err := parsePackage(b, fs, dir)
 	if err != nil {
 		return err
 	}
-	for pkg := range pkgs {
-		err = processPackage(path, pkgs[pkg])
+	var want []byte
+	var name string
+	if pkg != nil {
+		want, err = generatePackage(fs, dir, pkg)
 		if err != nil {
 			return err
 		}
+		name = renderFileName(dir, pkg.Name)
+	}
+	existing, err := filepath.Glob(filepath.Join(dir, "*_render_gen.go"))
+	if err != nil {
+		return err
+	}
+	var stale []string
+	for _, f := range existing {
+		if f == name && want != nil {
+			continue
+		}
+		old, err := ioutil.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		if err := unifiedDiff(w, f, "/dev/null", old, nil); err != nil {
+			return err
+		}
+		stale = append(stale, f)
+	}
+	if want != nil {
+		old, err := ioutil.ReadFile(name)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if !bytes.Equal(old, want) {
+			oldName := name
+			if old == nil {
+				oldName = "/dev/null"
+			}
+			if err := unifiedDiff(w, oldName, name, old, want); err != nil {
+				return err
+			}
+			stale = append(stale, name)
+		}
+	}
+	if len(stale) > 0 {
+		return &staleError{files: stale}
 	}
 	return nil
 }
 
-func renderFile(ctx *cli.Context) error {
-	return nil
+// staleError is returned by checkDir when generated files are out of date.
+type staleError struct {
+	files []string
+}
+
+func (e *staleError) Error() string {
+	return strings.Join(e.files, ", ") + " is out of date"
+}
+
+func renderFileName(dir, pkg string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s_render_gen.go", pkg))
+}
+
+// parsePackage parses go files of the package in dir excluding tests and
+// previously generated render files. This returns nil if dir has no go files.
+func parsePackage(b *build.Context, fs *token.FileSet, dir string) (*ast.Package, error) {
+	p, err := b.ImportDir(dir, 0)
+	if err != nil {
+		if _, ok := err.(*build.NoGoError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	pkg := &ast.Package{
+		Name:  p.Name,
+		Files: make(map[string]*ast.File),
+	}
+	files := append(append([]string{}, p.GoFiles...), p.CgoFiles...)
+	for _, name := range files {
+		if strings.HasSuffix(name, "_render_gen.go") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		f, err := parser.ParseFile(fs, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		pkg.Files[path] = f
+	}
+	return pkg, nil
+}
+
+// templateExt are extensions of files that can hold component templates.
+var templateExt = []string{".html", ".gohtml"}
+
+// templateFile returns the template file for component name found in dir by
+// naming convention, which is the component name in lower case or snake case
+// with one of templateExt. An empty string is returned if there is no such
+// file.
+func templateFile(dir, name string) string {
+	for _, base := range []string{strings.ToLower(name), snakeCase(name)} {
+		for _, ext := range templateExt {
+			path := filepath.Join(dir, base+ext)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+func snakeCase(s string) string {
+	var buf bytes.Buffer
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				buf.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// directive returns the argument of a //greact:name comment in doc.
+func directive(doc *ast.CommentGroup, name string) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+	prefix := "//greact:" + name
+	for _, c := range doc.List {
+		if c.Text == prefix {
+			return "", true
+		}
+		if strings.HasPrefix(c.Text, prefix+" ") {
+			return strings.TrimSpace(strings.TrimPrefix(c.Text, prefix)), true
+		}
+	}
+	return "", false
+}
+
+// warnings is where warnings about skipped components are written.
+var warnings io.Writer = os.Stderr
+
+func warnf(format string, args ...interface{}) {
+	fmt.Fprintf(warnings, "warning: "+format+"\n", args...)
 }
 
 func takeFile(f *ast.File) bool {
+	return greactName(f) != ""
+}
+
+// greactName returns the name greact package is imported as in f. This is "."
+// for dot imports and an empty string when f doesn't import greact.
+func greactName(f *ast.File) string {
 	for _, i := range f.Imports {
-		if i.Path.Value == packageImport {
-			return true
+		if p, _ := strconv.Unquote(i.Path.Value); p == packageImport {
+			if i.Name != nil {
+				return i.Name.Name
+			}
+			return packageName
 		}
 	}
+	return ""
+}
+
+// embedsCore returns true if f is an embedded greact.Core field, name is what
+// greact package is imported as.
+func embedsCore(f *ast.Field, name string) bool {
+	if f.Names != nil {
+		return false
+	}
+	switch x := f.Type.(type) {
+	case *ast.SelectorExpr:
+		id, ok := x.X.(*ast.Ident)
+		return ok && id.Name == name && x.Sel.Name == "Core"
+	case *ast.Ident:
+		return name == "." && x.Name == "Core"
+	}
 	return false
 }
 
-func processPackage(path string, pkg *ast.Package) error {
+func sortedFiles(pkg *ast.Package) []*ast.File {
+	var names []string
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var files []*ast.File
+	for _, name := range names {
+		files = append(files, pkg.Files[name])
+	}
+	return files
+}
+
+// packageConsts returns package level constants which have explicit values.
+func packageConsts(files []*ast.File) map[string]ast.Expr {
+	m := make(map[string]ast.Expr)
+	for _, f := range files {
+		for _, d := range f.Decls {
+			if g, ok := d.(*ast.GenDecl); ok && g.Tok == token.CONST {
+				addConsts(m, g)
+			}
+		}
+	}
+	return m
+}
+
+func addConsts(m map[string]ast.Expr, g *ast.GenDecl) {
+	for _, spec := range g.Specs {
+		vs := spec.(*ast.ValueSpec)
+		if len(vs.Names) != len(vs.Values) {
+			continue
+		}
+		for i, n := range vs.Names {
+			m[n.Name] = vs.Values[i]
+		}
+	}
+}
+
+// templateString returns the template returned by the Template method fn. The
+// body must return a constant string, it can be a literal, a named constant or
+// concatenation of those. Constants declared in the body are also considered.
+// lit is the first string literal of the template, which is used for error
+// reporting.
+func templateString(fn *ast.FuncDecl, consts map[string]ast.Expr) (v string, lit *ast.BasicLit, ok bool) {
+	if fn.Type.Results.NumFields() != 1 || fn.Body == nil {
+		return
+	}
+	if xt, ok := fn.Type.Results.List[0].Type.(*ast.Ident); !ok || xt.Name != "string" {
+		return "", nil, false
+	}
+	n := len(fn.Body.List)
+	if n == 0 {
+		return
+	}
+	scope := consts
+	for _, stmt := range fn.Body.List[:n-1] {
+		d, ok := stmt.(*ast.DeclStmt)
+		if !ok {
+			return "", nil, false
+		}
+		g, ok := d.Decl.(*ast.GenDecl)
+		if !ok || g.Tok != token.CONST {
+			return "", nil, false
+		}
+		if len(scope) == len(consts) {
+			scope = make(map[string]ast.Expr)
+			for k, v := range consts {
+				scope[k] = v
+			}
+		}
+		addConsts(scope, g)
+	}
+	rs, ok := fn.Body.List[n-1].(*ast.ReturnStmt)
+	if !ok || len(rs.Results) != 1 {
+		return
+	}
+	return constString(rs.Results[0], scope, make(map[string]bool))
+}
+
+func constString(e ast.Expr, consts map[string]ast.Expr, seen map[string]bool) (string, *ast.BasicLit, bool) {
+	switch x := e.(type) {
+	case *ast.BasicLit:
+		if x.Kind != token.STRING {
+			return "", nil, false
+		}
+		v, err := strconv.Unquote(x.Value)
+		if err != nil {
+			return "", nil, false
+		}
+		return v, x, true
+	case *ast.ParenExpr:
+		return constString(x.X, consts, seen)
+	case *ast.BinaryExpr:
+		if x.Op != token.ADD {
+			return "", nil, false
+		}
+		a, lit, ok := constString(x.X, consts, seen)
+		if !ok {
+			return "", nil, false
+		}
+		b, blit, ok := constString(x.Y, consts, seen)
+		if !ok {
+			return "", nil, false
+		}
+		if lit == nil {
+			lit = blit
+		}
+		return a + b, lit, true
+	case *ast.Ident:
+		v, ok := consts[x.Name]
+		if !ok || seen[x.Name] {
+			return "", nil, false
+		}
+		seen[x.Name] = true
+		defer delete(seen, x.Name)
+		return constString(v, consts, seen)
+	}
+	return "", nil, false
+}
+
+func processPackage(fs *token.FileSet, path string, pkg *ast.Package) error {
+	b, err := generatePackage(fs, path, pkg)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		return nil
+	}
+	return ioutil.WriteFile(renderFileName(path, pkg.Name), b, 0600)
+}
+
+// generatePackage returns the source of the generated render file for pkg, the
+// returned source is type checked against the rest of the package. This returns
+// nil when there is no component to generate code for.
+func generatePackage(fs *token.FileSet, path string, pkg *ast.Package) ([]byte, error) {
+	c, err := discoverComponents(fs, path, pkg, warnf)
+	if err != nil {
+		return nil, err
+	}
+	if len(c) == 0 {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	m := componentMap(nil, c...)
+	err = resolveImports(path, sortedFiles(pkg), m, c)
+	if err != nil {
+		return nil, err
+	}
+	err = Generate(&buf, pkg.Name, m, c...)
+	if err != nil {
+		return nil, err
+	}
+	err = typeCheck(fs, path, pkg, buf.Bytes(), m, c)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// discoverComponents returns components of pkg sorted by name with their
+// parsed templates. Components that are skipped are reported to warn.
+func discoverComponents(fs *token.FileSet, path string, pkg *ast.Package, warn func(string, ...interface{})) ([]GeneratorContext, error) {
 	ctxs := make(map[string]GeneratorContext)
 
+	// templateFiles maps component names to the template file named by the
+	// greact:template directive.
+	templateFiles := make(map[string]string)
+
+	// typePos maps component names to the position of their declaration and
+	// methodPos to the position of their Template method.
+	typePos := make(map[string]token.Pos)
+	methodPos := make(map[string]token.Pos)
+
+	files := sortedFiles(pkg)
+	consts := packageConsts(files)
+	structs := structTypes(files)
+
 	// First we collect all structs that implements that emebds greact.Core. Then
 	// we check for the Template method which we then use to generate the render
 	// functions.
 	//
 	// The two iterations are important to allow the user to define the Template
 	// function in a separate file than the one which defines the component struct.
-	for _, file := range pkg.Files {
-		if !takeFile(file) {
+	for _, file := range files {
+		name := greactName(file)
+		if name == "" {
 			continue
 		}
 		for _, v := range file.Decls {
@@ -90,19 +764,27 @@ func processPackage(path string, pkg *ast.Package) error {
 				for _, spec := range g.Specs {
 					vs := spec.(*ast.TypeSpec)
 					if typ, ok := vs.Type.(*ast.StructType); ok {
-						if len(typ.Fields.List) > 0 {
-							for _, f := range typ.Fields.List {
-								if x, ok := f.Type.(*ast.SelectorExpr); ok {
-									if id, ok := x.X.(*ast.Ident); ok {
-										if f.Names == nil && id.Name == "greact" &&
-											x.Sel.Name == "Core" {
-											ctx := GeneratorContext{
-												StructName: vs.Name.Name,
-											}
-											ctxs[ctx.StructName] = ctx
-										}
-									}
+						for _, f := range typ.Fields.List {
+							if embedsCore(f, name) {
+								props, err := typedProps(fs, typ, structs, files)
+								if err != nil {
+									return nil, err
+								}
+								ctx := GeneratorContext{
+									StructName: vs.Name.Name,
+									Props:      props,
 								}
+								ctxs[ctx.StructName] = ctx
+								typePos[ctx.StructName] = vs.Pos()
+								doc := vs.Doc
+								if doc == nil && len(g.Specs) == 1 {
+									doc = g.Doc
+								}
+								if f, ok := directive(doc, "template"); ok {
+									templateFiles[ctx.StructName] = f
+								}
+								_, ctx.DOM = directive(doc, "dom")
+								ctxs[ctx.StructName] = ctx
 							}
 						}
 					}
@@ -110,7 +792,7 @@ func processPackage(path string, pkg *ast.Package) error {
 			}
 		}
 	}
-	for _, file := range pkg.Files {
+	for _, file := range files {
 		for _, v := range file.Decls {
 			if fn, ok := v.(*ast.FuncDecl); ok {
 				if fn.Recv != nil && fn.Name.Name == "Template" &&
@@ -120,51 +802,95 @@ func processPackage(path string, pkg *ast.Package) error {
 					if fd.Names != nil {
 						recv = fd.Names[0].Name
 					}
-
-					if typ, ok := fd.Type.(*ast.Ident); ok {
+					typ := fd.Type
+					if star, ok := typ.(*ast.StarExpr); ok {
+						typ = star.X
+					}
+					if typ, ok := typ.(*ast.Ident); ok {
 						if ctx, ok := ctxs[typ.Name]; ok {
 							ctx.Recv = recv
-							if fn.Type.Results.NumFields() == 1 {
-								o := fn.Type.Results.List[0]
-								if xt, ok := o.Type.(*ast.Ident); ok && xt.Name == "string" {
-									if len(fn.Body.List) == 1 {
-										rs := fn.Body.List[0].(*ast.ReturnStmt)
-										if len(rs.Results) == 1 {
-											if ret, ok := rs.Results[0].(*ast.BasicLit); ok {
-												v := strings.TrimPrefix(ret.Value, "`")
-												v = strings.TrimSuffix(v, "`")
-												n, err := ParseString(v)
-												if err != nil {
-													return err
-												}
-												ctx.Node = n
-												ctxs[ctx.StructName] = ctx
-											}
-										}
-									}
+							methodPos[ctx.StructName] = fn.Pos()
+							if v, lit, ok := templateString(fn, consts); ok {
+								n, err := ParseString(v)
+								if err != nil {
+									return nil, fmt.Errorf("%s: %v", fs.Position(lit.Pos()), err)
 								}
+								ctx.Node = n
+								ctx.Template = v
+								ctx.Pos = fs.Position(lit.Pos() + 1)
 							}
+							ctxs[ctx.StructName] = ctx
 						}
 					}
 				}
 			}
 		}
 	}
-	if len(ctxs) == 0 {
-		return nil
+	for name, ctx := range ctxs {
+		file, ok := templateFiles[name]
+		if ok && ctx.Node != nil {
+			return nil, fmt.Errorf("%s: component %s has both a Template method and a template file %s",
+				ctx.Pos, name, file)
+		}
+		if ctx.Node != nil {
+			continue
+		}
+		if ok {
+			if file == "" {
+				return nil, fmt.Errorf("component %s: greact:template requires a file name", name)
+			}
+			file = filepath.Join(path, file)
+		} else {
+			file = templateFile(path, name)
+			if file == "" {
+				continue
+			}
+		}
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		n, err := ParseString(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		ctx.Node = n
+		ctx.Template = string(b)
+		ctx.Pos = token.Position{Filename: file, Line: 1, Column: 1}
+		if ctx.Recv == "" {
+			// Following go conventions the receiver is the first letter of the
+			// type name.
+			ctx.Recv = strings.ToLower(name[:1])
+		}
+		ctxs[name] = ctx
+	}
+	var names []string
+	for name := range ctxs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ctxs[name].Node != nil {
+			continue
+		}
+		if pos, ok := methodPos[name]; ok {
+			warn("%s: component %s is skipped, its Template method must return a constant string",
+				fs.Position(pos), name)
+		} else {
+			warn("%s: component %s embeds greact.Core but has no template",
+				fs.Position(typePos[name]), name)
+		}
+		delete(ctxs, name)
 	}
 	var c []GeneratorContext
 	for _, v := range ctxs {
+		if v.Recv == "" && v.Props != nil {
+			v.Recv = strings.ToLower(v.StructName[:1])
+		}
 		c = append(c, v)
 	}
 	sort.Slice(c, func(i, j int) bool {
 		return c[i].StructName < c[j].StructName
 	})
-	var buf bytes.Buffer
-	err := Generate(&buf, pkg.Name, nil, c...)
-	if err != nil {
-		return err
-	}
-	n := filepath.Join(path, fmt.Sprintf("%s_render_gen.go", pkg.Name))
-	return ioutil.WriteFile(n, buf.Bytes(), 0600)
+	return c, nil
 }
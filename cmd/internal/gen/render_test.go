@@ -0,0 +1,610 @@
+package gen
+
+import (
+	"bytes"
+	"go/build"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPackageDirs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "greact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, d := range []string{
+		"a/b", "a/testdata/c", "vendor/d", ".git", "_e", "f",
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file := filepath.Join(dir, "f", "f.go")
+	if err := ioutil.WriteFile(file, []byte("package f\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sample := []struct {
+		args   []string
+		expect []string
+	}{
+		{[]string{dir + "/..."}, []string{
+			dir, dir + "/a", dir + "/a/b", dir + "/f",
+		}},
+		{[]string{dir + "/a", file, dir + "/a/..."}, []string{
+			dir + "/a", dir + "/f", dir + "/a/b",
+		}},
+	}
+	for _, v := range sample {
+		got, err := packageDirs(v.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, v.expect) {
+			t.Errorf("expected %v got %v", v.expect, got)
+		}
+	}
+	if _, err := packageDirs(filepath.Join(dir, "f", "missing.go")); err == nil {
+		t.Error("expected an error for missing file")
+	}
+}
+
+func TestParsePackageTags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "greact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"a.go":            "package a\n",
+		"b.go":            "// +build special\n\npackage a\n",
+		"a_test.go":       "package a\n",
+		"a_render_gen.go": "package a\n",
+	}
+	for k, v := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, k), []byte(v), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names := func(tags ...string) []string {
+		b := build.Default
+		b.BuildTags = tags
+		pkg, err := parsePackage(&b, token.NewFileSet(), dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n []string
+		for k := range pkg.Files {
+			n = append(n, filepath.Base(k))
+		}
+		sort.Strings(n)
+		return n
+	}
+	if n := names(); !reflect.DeepEqual(n, []string{"a.go"}) {
+		t.Errorf("expected [a.go] got %v", n)
+	}
+	if n := names("special"); !reflect.DeepEqual(n, []string{"a.go", "b.go"}) {
+		t.Errorf("expected [a.go b.go] got %v", n)
+	}
+}
+
+func TestCheckDir(t *testing.T) {
+	// The directory must be inside the module for imports to be resolved.
+	dir, err := ioutil.TempDir("fixture", "check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src, err := ioutil.ReadFile("fixture/check/valid/valid.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "valid.go"), src, 0600); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := checkDir(&build.Default, dir, &buf); err == nil {
+		t.Fatal("expected missing file to be reported")
+	}
+	if !strings.HasPrefix(buf.String(), "--- /dev/null\n") {
+		t.Errorf("expected diff against /dev/null got\n%s", buf.String())
+	}
+
+	if err := renderDir(&build.Default, dir); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := checkDir(&build.Default, dir, &buf); err != nil {
+		t.Fatalf("expected up to date got %v\n%s", err, buf.String())
+	}
+
+	// A generated file that has no component left is stale.
+	src = bytes.Replace(src, []byte("greact.Core"), []byte("Core greact.Core"), 1)
+	if err := ioutil.WriteFile(filepath.Join(dir, "valid.go"), src, 0600); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	err = checkDir(&build.Default, dir, &buf)
+	if err == nil {
+		t.Fatal("expected stale file to be reported")
+	}
+	expect := filepath.Join(dir, "valid_render_gen.go") + " is out of date"
+	if err.Error() != expect {
+		t.Errorf("expected %s got %v", expect, err)
+	}
+	if !strings.Contains(buf.String(), "+++ /dev/null\n") {
+		t.Errorf("expected diff against /dev/null got\n%s", buf.String())
+	}
+}
+
+func TestRenderDirsCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("fixture", "check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var dirs []string
+	for _, name := range []string{"valid", "invalid"} {
+		src, err := ioutil.ReadFile(filepath.Join("fixture/check", name, name+".go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := filepath.Join(dir, name)
+		if err := os.Mkdir(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(d, name+".go"), src, 0600); err != nil {
+			t.Fatal(err)
+		}
+		dirs = append(dirs, d)
+	}
+	var out, errs bytes.Buffer
+	err = renderDirs(&build.Default, dirs, true, &out, &errs)
+	expect := "render: failed to check 1 of 2 packages, 1 of 2 packages are out of date"
+	if err == nil || err.Error() != expect {
+		t.Errorf("expected %s got %v", expect, err)
+	}
+	if !strings.Contains(errs.String(), filepath.Join(dirs[0], "valid_render_gen.go")+" is out of date") {
+		t.Errorf("expected valid to be out of date got\n%s", errs.String())
+	}
+
+	if err := renderDir(&build.Default, dirs[0]); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	errs.Reset()
+	err = renderDirs(&build.Default, dirs, true, &out, &errs)
+	expect = "render: failed to check 1 of 2 packages"
+	if err == nil || err.Error() != expect {
+		t.Errorf("expected %s got %v", expect, err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no diff got\n%s", out.String())
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	b := "a\nb\nc\nD\ne\nf\ng\nh\ni\nj\nk\n"
+	expect := `--- a
++++ b
+@@ -1,10 +1,11 @@
+ a
+ b
+ c
+-d
++D
+ e
+ f
+ g
+ h
+ i
+ j
++k
+`
+	var buf bytes.Buffer
+	if err := unifiedDiff(&buf, "a", "b", []byte(a), []byte(b)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, buf.String())
+	}
+	buf.Reset()
+	a = "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b = "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n"
+	expect = `--- a
++++ b
+@@ -1,3 +1,4 @@
++0
+ 1
+ 2
+ 3
+@@ -9,4 +10,3 @@
+ 9
+ 10
+ 11
+-12
+`
+	if err := unifiedDiff(&buf, "a", "b", []byte(a), []byte(b)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, buf.String())
+	}
+	buf.Reset()
+	if err := unifiedDiff(&buf, "a", "b", []byte(a), []byte(a)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output for equal input got\n%s", buf.String())
+	}
+}
+
+func TestTemplateFiles(t *testing.T) {
+	b, err := generateDir(t, "fixture/external")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect, err := ioutil.ReadFile("fixture/external/external.go.out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, expect) {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, b)
+	}
+}
+
+func TestTemplateFileErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("fixture", "external")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := `package external
+
+import "github.com/gernest/greact"
+
+type Hello struct {
+	greact.Core
+	Name string
+}
+`
+	tpl := `<div>
+	<p>{h.Nmae}</p>
+</div>
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "hello.go"), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "hello.html"), []byte(tpl), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = generateDir(t, dir)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	expect := filepath.Join(dir, "hello.html") + ":2:5: Hello: {h.Nmae}: h.Nmae undefined (type *Hello has no field or method Nmae)"
+	if err.Error() != expect {
+		t.Errorf("expected %s got %v", expect, err)
+	}
+}
+
+func TestWatchTemplateDirs(t *testing.T) {
+	dir, err := ioutil.TempDir("fixture", "watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := `package watch
+
+import "github.com/gernest/greact"
+
+//greact:template sub/hello.html
+type Hello struct {
+	greact.Core
+}
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "hello.go"), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	tpl := filepath.Join(dir, "sub", "hello.html")
+	if err := ioutil.WriteFile(tpl, []byte("<p>one</p>"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "watch_render_gen.go")
+	waitFor := func(text string) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			if b, _ := ioutil.ReadFile(out); bytes.Contains(b, []byte(text)) {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("expected %s to be regenerated with %s", out, text)
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		watchDirs(&build.Default, []string{dir}, 10*time.Millisecond, stop)
+		close(done)
+	}()
+	defer func() {
+		close(stop)
+		<-done
+	}()
+	waitFor(`"one"`)
+	// let the watcher record the first state of the files.
+	time.Sleep(50 * time.Millisecond)
+	if err := ioutil.WriteFile(tpl, []byte("<p>three</p>"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(`"three"`)
+}
+
+func TestDiscovery(t *testing.T) {
+	var w bytes.Buffer
+	warnings = &w
+	defer func() {
+		warnings = os.Stderr
+	}()
+	b, err := generateDir(t, "fixture/discovery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect, err := ioutil.ReadFile("fixture/discovery/discovery.go.out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, expect) {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, b)
+	}
+	expectWarnings := `warning: fixture/discovery/alias.go:32:1: component Dynamic is skipped, its Template method must return a constant string
+warning: fixture/discovery/alias.go:23:6: component NoTemplate embeds greact.Core but has no template
+`
+	if w.String() != expectWarnings {
+		t.Errorf("expected:\n%s\ngot:\n%s", expectWarnings, w.String())
+	}
+}
+
+func TestCrossPackage(t *testing.T) {
+	b, err := generateDir(t, "fixture/crosspkg/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect, err := ioutil.ReadFile("fixture/crosspkg/app/app.go.out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, expect) {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, b)
+	}
+}
+
+func TestTypedProps(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		b, err := generateDir(t, "fixture/props/valid")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect, err := ioutil.ReadFile("fixture/props/valid/valid.go.out")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, expect) {
+			t.Errorf("expected:\n%s\ngot:\n%s", expect, b)
+		}
+		buildGenerated(t, "fixture/props/valid", b)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := generateDir(t, "fixture/props/invalid")
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		expect := []string{
+			`fixture/props/invalid/invalid.go:29:15: Page: lable="Save": unknown prop lable for component FancyButton`,
+			`fixture/props/invalid/invalid.go:30:15: Page: size="3": prop size of FancyButton expects int, text is passed as string`,
+			`fixture/props/invalid/invalid.go:30:24: Page: label={4}: cannot use int as string value in prop label of FancyButton`,
+			`fixture/props/invalid/invalid.go:31:15: Page: scale={3}: cannot use int as int64 value in prop scale of FancyButton`,
+			`fixture/props/invalid/invalid.go:31:25: Page: kind="primary": prop kind of FancyButton expects Kind, text is passed as string`,
+		}
+		got := strings.Split(err.Error(), "\n")
+		if !reflect.DeepEqual(got, expect) {
+			t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(expect, "\n"), err)
+		}
+	})
+}
+
+func TestDOMMode(t *testing.T) {
+	b, err := generateDir(t, "fixture/dommode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect, err := ioutil.ReadFile("fixture/dommode/dommode.go.out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, expect) {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, b)
+	}
+	buildGenerated(t, "fixture/dommode", b)
+}
+
+func TestRenderStaticRoot(t *testing.T) {
+	dir, err := ioutil.TempDir("fixture", "static")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := `package static
+
+import "github.com/gernest/greact"
+
+type Page struct {
+	greact.Core
+}
+
+func (p Page) Template() string {
+	return ` + "`" + `<div class="page"><header><h1>Title</h1></header></div>` + "`" + `
+}
+`
+	// Renders must not share the root, changing one must leave the others
+	// alone. Static nodes below it are shared.
+	test := `package static
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gernest/greact/node"
+)
+
+func TestRender(t *testing.T) {
+	a := (&Page{}).Render(context.Background(), nil, nil)
+	b := (&Page{}).Render(context.Background(), nil, nil)
+	if a.Static || a == b {
+		t.Fatal("expected a new root on every render")
+	}
+	a.Data = "section"
+	a.Attr[0] = node.Attr("", "class", "changed")
+	if b.Data != "div" || b.Attr[0].Val.(node.ClassList).String() != "page" {
+		t.Errorf("changing a render changed another one: <%s %v>", b.Data, b.Attr[0].Val)
+	}
+	if c := b.Attr[1].Val.([]*node.Node); !c[0].Static {
+		t.Error("expected the header to be static")
+	}
+}
+`
+	for name, v := range map[string]string{"static.go": src, "static_test.go": test} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(v), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := renderDir(&build.Default, dir); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("go", "test", ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+}
+
+func TestPositionMapper(t *testing.T) {
+	dir, err := ioutil.TempDir("fixture", "external")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := `package external
+
+import "github.com/gernest/greact"
+
+type Hello struct {
+	greact.Core
+	Name string
+}
+`
+	tpl := `<div>
+	<p>{h.Name}</p>
+	<b title={h.Name}>{h.Name}</b>
+</div>`
+	if err := ioutil.WriteFile(filepath.Join(dir, "hello.go"), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "hello.html"), []byte(tpl), 0600); err != nil {
+		t.Fatal(err)
+	}
+	b, err := generateDir(t, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := renderFileName(dir, "external")
+	if err := ioutil.WriteFile(file, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	// positions of h.Name in the generated file, in order.
+	var found []token.Position
+	for i, v := range strings.Split(string(b), "\n") {
+		for col := 0; ; {
+			j := strings.Index(v[col:], "h.Name")
+			if j == -1 {
+				break
+			}
+			col += j + 1
+			found = append(found, token.Position{Filename: file, Line: i + 1, Column: col})
+		}
+	}
+	html := filepath.Join(dir, "hello.html")
+	expect := []string{html + ":2:5", html + ":3:5", html + ":3:20"}
+	if len(found) != len(expect) {
+		t.Fatalf("expected %d uses of h.Name got %d:\n%s", len(expect), len(found), b)
+	}
+	m := NewPositionMapper()
+	for i, pos := range found {
+		got, name, ok := m.Position(pos)
+		if !ok {
+			t.Fatalf("expected %s to be mapped", pos)
+		}
+		if name != "Hello" {
+			t.Errorf("expected Hello got %s", name)
+		}
+		if got.String() != expect[i] {
+			t.Errorf("%s: expected %s got %s", pos, expect[i], got)
+		}
+	}
+	if len(m.files) != 1 {
+		t.Errorf("expected the file to be parsed once, got %d entries", len(m.files))
+	}
+	if _, _, ok := m.Position(token.Position{Filename: file, Line: 1}); ok {
+		t.Error("expected the package clause not to be mapped")
+	}
+}
+
+// buildGenerated compiles the package in dir with the generated render source
+// b the way apps are built, for wasm.
+func buildGenerated(t *testing.T, dir string, b []byte) {
+	t.Helper()
+	// The directory must be inside the module for imports to be resolved.
+	tmp, err := ioutil.TempDir("fixture", "build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") || strings.HasSuffix(f, "_render_gen.go") {
+			continue
+		}
+		src, err := ioutil.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(tmp, filepath.Base(f)), src, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmp, "build_render_gen.go"), b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("go", "build", ".")
+	cmd.Dir = tmp
+	cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated code of %s does not build: %v\n%s", dir, err, out)
+	}
+}
@@ -0,0 +1,209 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/urfave/cli"
+)
+
+// defaultSpec is the path of the html spec data, relative to the root of the
+// repository where the generator commands are run.
+const defaultSpec = "spec/html.json"
+
+// spec is html data the elements and attribute packages are generated from.
+// It is kept in the repository so generation is reproducible and needs no
+// network, the spec command refreshes it.
+type spec struct {
+	// Version identifies the data, the spec command sets it to the date the
+	// pages were fetched.
+	Version string `json:"version"`
+
+	// Sources are urls of the pages the data was extracted from.
+	Sources []string `json:"sources"`
+
+	Elements   []string    `json:"elements"`
+	Attributes []attribute `json:"attributes"`
+}
+
+type attribute struct {
+	Name string `json:"name"`
+
+	// Global is true for attributes that apply to all elements, Elements is
+	// empty for them.
+	Global   bool     `json:"global,omitempty"`
+	Elements []string `json:"elements,omitempty"`
+}
+
+func loadSpec(path string) (*spec, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &spec{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return s, nil
+}
+
+func (s *spec) save(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+var (
+	elementName   = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	attributeName = regexp.MustCompile(`^[a-z][a-z0-9-]*\*?$`)
+)
+
+// cleanName trims s, including non breaking spaces, removes angle brackets
+// and lower cases it.
+func cleanName(s string) string {
+	s = strings.NewReplacer("<", "", ">", "").Replace(s)
+	return strings.ToLower(strings.TrimFunc(s, unicode.IsSpace))
+}
+
+// isGlobal returns true if the elements cell of an attribute marks it as a
+// global attribute.
+func isGlobal(s string) bool {
+	return strings.Replace(cleanName(s), " ", "", -1) == "globalattribute"
+}
+
+// clean normalizes names scraped from documentation pages. Lists like
+// "h1, h2" are split, names that are not element or attribute names are
+// dropped, attributes are merged by name and elements of attributes must be
+// known elements.
+func (s *spec) clean() {
+	elems := make(map[string]bool)
+	for _, v := range s.Elements {
+		for _, e := range strings.Split(v, ",") {
+			if e = cleanName(e); elementName.MatchString(e) {
+				elems[e] = true
+			}
+		}
+	}
+	s.Elements = s.Elements[:0]
+	for e := range elems {
+		s.Elements = append(s.Elements, e)
+	}
+	sort.Strings(s.Elements)
+
+	attrs := make(map[string]*attribute)
+	for _, a := range s.Attributes {
+		name := cleanName(a.Name)
+		if !attributeName.MatchString(name) {
+			continue
+		}
+		m, ok := attrs[name]
+		if !ok {
+			m = &attribute{Name: name}
+			attrs[name] = m
+		}
+		m.Global = m.Global || a.Global
+		for _, v := range a.Elements {
+			for _, e := range strings.Split(v, ",") {
+				if isGlobal(e) {
+					m.Global = true
+					continue
+				}
+				if e = cleanName(e); elems[e] {
+					m.Elements = append(m.Elements, e)
+				}
+			}
+		}
+	}
+	s.Attributes = s.Attributes[:0]
+	for _, a := range attrs {
+		if a.Global {
+			a.Elements = nil
+		}
+		a.Elements = dedupe(a.Elements)
+		s.Attributes = append(s.Attributes, *a)
+	}
+	sort.Slice(s.Attributes, func(i, j int) bool {
+		return s.Attributes[i].Name < s.Attributes[j].Name
+	})
+}
+
+// dedupe returns sorted v without duplicates.
+func dedupe(v []string) []string {
+	if len(v) == 0 {
+		return nil
+	}
+	sort.Strings(v)
+	out := v[:1]
+	for _, s := range v[1:] {
+		if s != out[len(out)-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// extraElements are elements missing from the element reference tables.
+var extraElements = []string{
+	"base",
+	"section",
+	"h1",
+	"h2",
+	"h3",
+	"h4",
+	"h5",
+	"h6",
+	"iframe",
+}
+
+// fetchSpec scrapes the element and attribute references of MDN.
+func fetchSpec() (*spec, error) {
+	s := &spec{
+		Version: time.Now().UTC().Format("2006-01-02"),
+		Sources: []string{mozElementReference, mozAttributeReference},
+	}
+	doc, err := goquery.NewDocument(mozElementReference)
+	if err != nil {
+		return nil, err
+	}
+	s.Elements = append(extractElements(doc), extraElements...)
+	doc, err = goquery.NewDocument(mozAttributeReference)
+	if err != nil {
+		return nil, err
+	}
+	s.Attributes = extractAttr(doc)
+	s.clean()
+	return s, nil
+}
+
+// SpecCMD is a cli command that refreshes the html spec data.
+func SpecCMD() cli.Command {
+	return cli.Command{
+		Name:  "spec",
+		Usage: "updates the html spec data the elems and attr commands generate from",
+		Description: `The element and attribute references of MDN are downloaded and the names
+   they list are cleaned up and written as json. Review the changes to the
+   file before regenerating the packages with elems and attr.`,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "out",
+				Value: defaultSpec,
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			s, err := fetchSpec()
+			if err != nil {
+				return err
+			}
+			return s.save(ctx.String("out"))
+		},
+	}
+}
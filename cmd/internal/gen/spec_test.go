@@ -0,0 +1,70 @@
+package gen
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestSpecClean(t *testing.T) {
+	s := &spec{
+		Elements: []string{"<div>", "h1, h2", "img", "p", "p", "Global attribute"},
+		Attributes: []attribute{
+			{Name: "title", Elements: []string{"Global attribute"}},
+			{Name: "loading  ", Elements: []string{"img", " iframe", "contenteditable"}},
+			{Name: "data-*", Elements: []string{"Globalattribute"}},
+			{Name: "loading", Elements: []string{"img"}},
+			{Name: "Not an attribute", Elements: []string{"div"}},
+		},
+	}
+	s.clean()
+	expectElements := []string{"div", "h1", "h2", "img", "p"}
+	if !reflect.DeepEqual(s.Elements, expectElements) {
+		t.Errorf("expected elements %v got %v", expectElements, s.Elements)
+	}
+	expectAttributes := []attribute{
+		{Name: "data-*", Global: true},
+		{Name: "loading", Elements: []string{"img"}},
+		{Name: "title", Global: true},
+	}
+	if !reflect.DeepEqual(s.Attributes, expectAttributes) {
+		t.Errorf("expected attributes %v got %v", expectAttributes, s.Attributes)
+	}
+}
+
+// TestGenerated checks the elements and attribute packages are generated
+// from the spec data in the repository.
+func TestGenerated(t *testing.T) {
+	s, err := loadSpec("../../../" + defaultSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.clean()
+	elems, err := generateElements(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := generateAttributes(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+	attrs, err := format.Source(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sample := map[string][]byte{
+		"../../../elements/elements.go":    elems,
+		"../../../attribute/attributes.go": attrs,
+	}
+	for path, expect := range sample {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, expect) {
+			t.Errorf("%s is out of date, run greact_gen elems and greact_gen attr", path)
+		}
+	}
+}
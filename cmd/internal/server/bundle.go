@@ -0,0 +1,412 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gernest/greact/cmd/internal/gen"
+	"github.com/urfave/cli"
+)
+
+// manifestName is the name of the file that maps names of bundled assets to
+// their hashed names.
+const manifestName = "manifest.json"
+
+// Build defines the build command which writes a bundle that is ready to be
+// deployed.
+func Build() cli.Command {
+	return cli.Command{
+		Name:      "build",
+		Usage:     "builds a bundle of the app for deployment",
+		ArgsUsage: "[dir]",
+		Description: `Render files of the package in the given directory, or the current
+   directory, and its sub directories are generated, then the package is built
+   to a wasm module stripped of debug information and file system paths.
+
+   The bundle is written to the dist directory, which is emptied first. It
+   must not hold the package, and must neither hold nor be inside the static
+   directory, the shell or the mock directory. The bundle has index.html
+   executed from the shell, wasm_exec.js, the module and files of the static
+   directory. An index.html in the static directory is ignored.
+   Assets get the hash of their content in their names so they can be cached
+   forever, except html files and favicon.ico and robots.txt at the root of
+   the static directory which are looked up by name. ` + manifestName + ` maps
+   names of assets to their hashed names, shells link static files with
+   {{asset "/style.css"}} which is the hashed name in the bundle.
+
+   Text files and the module are also written compressed with gzip and brotli,
+   as .gz and .br files, so servers can send them without compressing on each
+   request. Settings are read from ` + configName + ` like for serve.`,
+		Flags: append(buildFlags(),
+			cli.StringFlag{
+				Name:  "dist",
+				Usage: "directory the bundle is written to, defaults to dist in the package directory",
+			},
+		),
+		Action: buildBundle,
+	}
+}
+
+func buildBundle(ctx *cli.Context) error {
+	a := ctx.Args().First()
+	if a == "" {
+		a = "."
+	}
+	a, err := filepath.Abs(a)
+	if err != nil {
+		return err
+	}
+	c, err := loadConfig(ctx, a)
+	if err != nil {
+		return err
+	}
+	dirs, err := gen.PackageDirs(filepath.Join(a, "..."))
+	if err != nil {
+		return err
+	}
+	if err := renderDirs(dirs, c.buildTags()); err != nil {
+		return err
+	}
+	b, err := newBundle(a, c, nil)
+	if err != nil {
+		return err
+	}
+	return b.summary(os.Stdout)
+}
+
+// bundle is a directory of files to deploy.
+type bundle struct {
+	dir string
+
+	// manifest maps names of assets to their hashed names.
+	manifest map[string]string
+
+	// files are names of files written to dir, without compressed files.
+	files []string
+}
+
+// newBundle builds the package in dir with c and writes the bundle to c.Dist.
+// pages maps routes to their html which is the content of the shell for the
+// route, index.html is an empty shell unless there is a page for /.
+func newBundle(dir string, c *config, pages map[string]string) (*bundle, error) {
+	if err := checkDist(dir, c); err != nil {
+		return nil, err
+	}
+	tmp, err := ioutil.TempDir("", "greact-build")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	bc := *c
+	bc.Out = tmp
+	bc.Ldflags = strings.TrimSpace("-s -w " + c.Ldflags)
+	bc.Trimpath = true
+	if err := build(dir, &bc); err != nil {
+		return nil, err
+	}
+	js, err := wasmExec()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(c.Dist); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(c.Dist, 0755); err != nil {
+		return nil, err
+	}
+	b := &bundle{dir: c.Dist, manifest: make(map[string]string)}
+	wasm, err := b.copyHashed("main.wasm", filepath.Join(tmp, "main.wasm"))
+	if err != nil {
+		return nil, err
+	}
+	execJS, err := b.copyHashed("wasm_exec.js", js)
+	if err != nil {
+		return nil, err
+	}
+	if c.Static != "" {
+		if err := b.addStatic(c.Static); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.writePages(c, wasm, execJS, pages); err != nil {
+		return nil, err
+	}
+	m, err := json.MarshalIndent(b.manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := b.write(manifestName, append(m, '\n')); err != nil {
+		return nil, err
+	}
+	return b, b.compress()
+}
+
+// writePages writes the shell for every page, wasm and execJS are the names
+// of the module and its loader in the bundle.
+func (b *bundle) writePages(c *config, wasm, execJS string, pages map[string]string) error {
+	if _, ok := pages["/"]; !ok {
+		if pages == nil {
+			pages = make(map[string]string)
+		}
+		pages["/"] = ""
+	}
+	for _, route := range sortedKeys(pages) {
+		page, err := renderShell(c.Shell, shellData{
+			Title:    c.Title,
+			Wasm:     "/" + wasm,
+			WasmExec: "/" + execJS,
+			Content:  template.HTML(pages[route]),
+			Assets:   b.manifest,
+		})
+		if err != nil {
+			return err
+		}
+		if err := b.write(pageFile(route), page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pageFile returns the name of the file of the page for route. Routes ending
+// with .html are files, others are directories with an index.html.
+func pageFile(route string) string {
+	name := strings.Trim(path.Clean("/"+route), "/")
+	switch {
+	case name == "":
+		return "index.html"
+	case path.Ext(name) == ".html":
+		return name
+	}
+	return name + "/index.html"
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkDist returns an error if emptying the dist directory of c would remove
+// the package in dir or the static files, shell or mocks of c, or if the
+// bundle would be written in among them.
+func checkDist(dir string, c *config) error {
+	if within(dir, c.Dist) {
+		return fmt.Errorf("dist directory %s holds the package, choose another one", c.Dist)
+	}
+	for _, v := range []struct {
+		name, path string
+	}{
+		{"static directory", c.Static},
+		{"shell", c.Shell},
+		{"mock directory", c.Mock},
+	} {
+		if v.path != "" && (within(v.path, c.Dist) || within(c.Dist, v.path)) {
+			return fmt.Errorf("dist directory %s overlaps the %s %s, choose another one", c.Dist, v.name, v.path)
+		}
+	}
+	return nil
+}
+
+// within returns true if path is dir or is inside it.
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && (rel == "." || !strings.HasPrefix(rel, ".."))
+}
+
+// unhashed are names of files at the root of the static directory that are
+// requested by name.
+var unhashed = map[string]bool{
+	"favicon.ico": true,
+	"robots.txt":  true,
+}
+
+// addStatic adds files in dir and its sub directories to the bundle.
+func (b *bundle) addStatic(dir string) error {
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if name == "index.html" {
+			// the shell is the index page.
+			return nil
+		}
+		if unhashed[name] || path.Ext(name) == ".html" {
+			data, err := ioutil.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			return b.write(name, data)
+		}
+		_, err = b.copyHashed(name, p)
+		return err
+	})
+}
+
+// copyHashed adds the file at src to the bundle as name with the hash of its
+// content, the hashed name is returned.
+func (b *bundle) copyHashed(name, src string) (string, error) {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return "", err
+	}
+	hashed := hashName(name, data)
+	b.manifest[name] = hashed
+	return hashed, b.write(hashed, data)
+}
+
+// hashName returns name with a hash of data before its extension.
+func hashName(name string, data []byte) string {
+	sum := sha256.Sum256(data)
+	h := hex.EncodeToString(sum[:])[:10]
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + h + ext
+}
+
+func (b *bundle) write(name string, data []byte) error {
+	p := filepath.Join(b.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(p, data, 0644); err != nil {
+		return err
+	}
+	b.files = append(b.files, name)
+	return nil
+}
+
+// compressible are extensions of files that are worth compressing, others
+// like images are compressed already.
+var compressible = map[string]bool{
+	".css":  true,
+	".html": true,
+	".js":   true,
+	".json": true,
+	".map":  true,
+	".svg":  true,
+	".txt":  true,
+	".wasm": true,
+	".xml":  true,
+}
+
+// compress writes gzip and brotli versions of compressible files. They are
+// skipped when they are not smaller than the file.
+func (b *bundle) compress() error {
+	for _, name := range b.files {
+		if !compressible[path.Ext(name)] {
+			continue
+		}
+		p := filepath.Join(b.dir, filepath.FromSlash(name))
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		gz, err := gzipBytes(data)
+		if err != nil {
+			return err
+		}
+		br, err := brotliBytes(data)
+		if err != nil {
+			return err
+		}
+		for ext, v := range map[string][]byte{".gz": gz, ".br": br} {
+			if len(v) >= len(data) {
+				continue
+			}
+			if err := ioutil.WriteFile(p+ext, v, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func brotliBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := brotli.NewWriterLevel(&buf, brotli.BestCompression)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// summary prints files of the bundle with their sizes.
+func (b *bundle) summary(w io.Writer) error {
+	files := append([]string(nil), b.files...)
+	sort.Strings(files)
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "file\tsize\tgzip\tbrotli\t")
+	for _, name := range files {
+		p := filepath.Join(b.dir, filepath.FromSlash(name))
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\n", name, fileSize(p), fileSize(p+".gz"), fileSize(p+".br"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "bundle written to %s\n", b.dir)
+	return err
+}
+
+// fileSize returns the size of the file at p in a readable form, or - when
+// there is no such file.
+func fileSize(p string) string {
+	info, err := os.Stat(p)
+	if err != nil {
+		return "-"
+	}
+	return formatSize(info.Size())
+}
+
+// formatSize returns n bytes in a readable form.
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fM", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fK", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%dB", n)
+}
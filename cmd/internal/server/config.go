@@ -0,0 +1,270 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+// configName is the name of the config file looked up in the package
+// directory when --config is not given.
+const configName = "greact.json"
+
+// defaultAddr is the address the server listens on by default.
+const defaultAddr = ":8099"
+
+// config holds settings of the serve command. They are read from a json file
+// and flags that are set override them.
+//
+//	{
+//		"addr": ":8080",
+//		"tags": "dev",
+//		"ldflags": "-s -w",
+//		"trimpath": true,
+//		"out": "build",
+//		"static": "public",
+//		"shell": "shell.html",
+//		"title": "App",
+//		"proxy": {"/api": "http://localhost:8080"},
+//		"mock": "mocks",
+//		"dist": "dist",
+//		"routes": {"/": "./pages.Home"},
+//		"routeTable": "./pages.Routes"
+//	}
+type config struct {
+	// Addr is the address to listen on.
+	Addr string `json:"addr"`
+
+	// Tags, Ldflags and Trimpath are passed to go build. Tags is a comma
+	// separated list of build tags, they are also used when rendering.
+	Tags     string `json:"tags"`
+	Ldflags  string `json:"ldflags"`
+	Trimpath bool   `json:"trimpath"`
+
+	// Out is the directory main.wasm is written to, it defaults to the package
+	// directory.
+	Out string `json:"out"`
+
+	// Static is a directory whose files are served alongside main.wasm.
+	Static string `json:"static"`
+
+	// Shell is an html/template file for the index page, see shellData for
+	// the values it is executed with and the asset func. Title is the page
+	// title, it defaults to the package directory name.
+	Shell string `json:"shell"`
+	Title string `json:"title"`
+
+	// Proxy maps path prefixes to urls of servers requests for them are sent
+	// to.
+	Proxy map[string]string `json:"proxy"`
+
+	// Mock is a directory of json fixtures that answer requests, see mocks.
+	Mock string `json:"mock"`
+
+	// Dist is the directory the build command writes the bundle to, it
+	// defaults to dist in the package directory.
+	Dist string `json:"dist"`
+
+	// Routes maps paths of pages the static command renders to components,
+	// which are given as package.Type. RouteTable names a package level
+	// map[string]node.Component variable with more routes, like
+	// package.Routes. Packages can be import paths or relative to the package
+	// directory.
+	Routes     map[string]string `json:"routes"`
+	RouteTable string            `json:"routeTable"`
+}
+
+// buildFlags are flags of commands that build the package, they override
+// config fields.
+func buildFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:  "config",
+			Usage: "path to the config file, defaults to " + configName + " in the package directory if it exists",
+		},
+		cli.StringFlag{
+			Name:  "tags",
+			Usage: "comma separated list of build tags",
+		},
+		cli.StringFlag{
+			Name:  "ldflags",
+			Usage: "flags passed to the go linker",
+		},
+		cli.BoolFlag{
+			Name:  "trimpath",
+			Usage: "remove file system paths from the compiled module",
+		},
+		cli.StringFlag{
+			Name:  "static",
+			Usage: "directory of static files served alongside main.wasm",
+		},
+		cli.StringFlag{
+			Name:  "shell",
+			Usage: "html template used for the index page",
+		},
+		cli.StringFlag{
+			Name:  "title",
+			Usage: "title of the index page, defaults to the package directory name",
+		},
+	}
+}
+
+// configFlags are flags of the serve command that override config fields.
+func configFlags() []cli.Flag {
+	return append(buildFlags(),
+		cli.StringFlag{
+			Name:  "addr",
+			Usage: "address to listen on",
+			Value: defaultAddr,
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "directory main.wasm is written to, defaults to the package directory",
+		},
+		cli.StringSliceFlag{
+			Name:  "proxy",
+			Usage: "send requests for a path prefix to another server, as prefix=url",
+		},
+		cli.StringFlag{
+			Name:  "mock",
+			Usage: "directory of json fixtures that answer matching requests",
+		},
+	)
+}
+
+// loadConfig returns the config of the package in dir. Relative paths in the
+// config file are relative to the directory of the file while those of flags
+// are relative to the working directory. Returned paths are absolute.
+func loadConfig(ctx *cli.Context, dir string) (*config, error) {
+	c := &config{Addr: defaultAddr}
+	path := ctx.String("config")
+	if path == "" {
+		path = filepath.Join(dir, configName)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			path = ""
+		}
+	}
+	if path != "" {
+		if err := readConfig(path, c); err != nil {
+			return nil, err
+		}
+	}
+	if ctx.IsSet("addr") {
+		c.Addr = ctx.String("addr")
+	}
+	if ctx.IsSet("tags") {
+		c.Tags = ctx.String("tags")
+	}
+	if ctx.IsSet("ldflags") {
+		c.Ldflags = ctx.String("ldflags")
+	}
+	if ctx.IsSet("trimpath") {
+		c.Trimpath = ctx.Bool("trimpath")
+	}
+	if ctx.IsSet("title") {
+		c.Title = ctx.String("title")
+	}
+	for _, v := range ctx.StringSlice("proxy") {
+		prefix, target, err := parseProxy(v)
+		if err != nil {
+			return nil, err
+		}
+		if c.Proxy == nil {
+			c.Proxy = make(map[string]string)
+		}
+		c.Proxy[prefix] = target
+	}
+	for _, v := range []struct {
+		flag string
+		dst  *string
+	}{
+		{"out", &c.Out},
+		{"static", &c.Static},
+		{"shell", &c.Shell},
+		{"mock", &c.Mock},
+		{"dist", &c.Dist},
+	} {
+		if !ctx.IsSet(v.flag) {
+			continue
+		}
+		p, err := filepath.Abs(ctx.String(v.flag))
+		if err != nil {
+			return nil, err
+		}
+		*v.dst = p
+	}
+	if c.Out == "" {
+		c.Out = dir
+	}
+	if c.Title == "" {
+		c.Title = filepath.Base(dir)
+	}
+	if c.Dist == "" {
+		c.Dist = filepath.Join(dir, "dist")
+	}
+	for _, dir := range []string{c.Static, c.Mock} {
+		if dir == "" {
+			continue
+		}
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", dir)
+		}
+	}
+	return c, nil
+}
+
+// readConfig reads the json config file at path into c.
+func readConfig(path string, c *config) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, c); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	base, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	for _, p := range []*string{&c.Out, &c.Static, &c.Shell, &c.Mock, &c.Dist} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(base, *p)
+		}
+	}
+	return nil
+}
+
+// buildArgs returns arguments of go build for c writing the module to out.
+func (c *config) buildArgs(out string) []string {
+	args := []string{"build", "-o", out}
+	if c.Tags != "" {
+		args = append(args, "-tags", c.Tags)
+	}
+	if c.Ldflags != "" {
+		args = append(args, "-ldflags", c.Ldflags)
+	}
+	if c.Trimpath {
+		args = append(args, "-trimpath")
+	}
+	return append(args, ".")
+}
+
+// buildTags returns the list of build tags in c.
+func (c *config) buildTags() []string {
+	var tags []string
+	for _, t := range strings.Split(c.Tags, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gernest/greact/cmd/internal/gen"
+)
+
+// buildError is returned when go build fails, Output is what the compiler
+// printed.
+type buildError struct {
+	Dir    string
+	Output string
+	Err    error
+}
+
+func (e *buildError) Error() string {
+	s := strings.TrimSpace(mapErrors(e.Dir, e.Output))
+	if s == "" {
+		return e.Err.Error()
+	}
+	return s
+}
+
+// compilerError matches positions of errors printed by the go compiler.
+var compilerError = regexp.MustCompile(`^(\S+\.go):(\d+):(\d+): (.*)$`)
+
+// mapErrors rewrites errors in generated render files of output to point to
+// the templates they were generated from. Relative file names are resolved
+// from dir, which is where go build ran. Each file is parsed once however many
+// errors it has.
+func mapErrors(dir, output string) string {
+	mapper := gen.NewPositionMapper()
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		m := compilerError.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		file := m[1]
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		ln, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		pos, name, ok := mapper.Position(token.Position{
+			Filename: file,
+			Line:     ln,
+			Column:   col,
+		})
+		if !ok {
+			continue
+		}
+		if rel, err := filepath.Rel(dir, pos.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			pos.Filename = rel
+		}
+		lines[i] = fmt.Sprintf("%s: %s: %s (generated %s:%s:%s)",
+			pos, name, m[4], filepath.Base(file), m[2], m[3])
+	}
+	return strings.Join(lines, "\n")
+}
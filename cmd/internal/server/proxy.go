@@ -0,0 +1,127 @@
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// proxyRule sends requests whose path is prefix or below it to proxy.
+type proxyRule struct {
+	prefix string
+	proxy  http.Handler
+}
+
+// parseProxy parses proxy rules of the form prefix=url.
+func parseProxy(rule string) (string, string, error) {
+	i := strings.IndexByte(rule, '=')
+	if i == -1 {
+		return "", "", fmt.Errorf("proxy %q: expected prefix=url", rule)
+	}
+	return rule[:i], rule[i+1:], nil
+}
+
+// newProxyRules returns rules for m which maps path prefixes to urls, longer
+// prefixes come first so they take precedence. Upgrade requests like web
+// sockets are proxied too.
+func newProxyRules(m map[string]string) ([]*proxyRule, error) {
+	var rules []*proxyRule
+	for prefix, target := range m {
+		if !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("proxy %s: prefix must start with /", prefix)
+		}
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("proxy %s: %v", prefix, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("proxy %s: %s is not an http url", prefix, target)
+		}
+		p := httputil.NewSingleHostReverseProxy(u)
+		director := p.Director
+		p.Director = func(r *http.Request) {
+			director(r)
+			r.Host = u.Host
+		}
+		rules = append(rules, &proxyRule{prefix: prefix, proxy: p})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if len(rules[i].prefix) != len(rules[j].prefix) {
+			return len(rules[i].prefix) > len(rules[j].prefix)
+		}
+		return rules[i].prefix < rules[j].prefix
+	})
+	return rules, nil
+}
+
+// match returns true if p is r.prefix or a path below it.
+func (r *proxyRule) match(p string) bool {
+	prefix := strings.TrimSuffix(r.prefix, "/")
+	return p == prefix || strings.HasPrefix(p, prefix+"/")
+}
+
+// mocks answers requests with json fixtures in a directory. The fixture of
+// GET /api/users/42 is api/users/42.json, a fixture named 42.post.json is
+// used for POST requests instead. Fixtures without a method only answer GET
+// and HEAD. Directories or files named _ match any path
+// segment, api/users/_.json answers for all users. Fixtures are read on each
+// request so they can be edited while serving.
+type mocks struct {
+	dir string
+}
+
+// serve writes the fixture of r and returns true if there is one.
+func (m *mocks) serve(w http.ResponseWriter, r *http.Request) bool {
+	p := path.Clean("/" + r.URL.Path)
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	file := m.find(m.dir, segments, strings.ToLower(r.Method))
+	if file == "" {
+		return false
+	}
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return true
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+	return true
+}
+
+// find returns the fixture in dir for the path segments, exact names are
+// preferred to wildcards.
+func (m *mocks) find(dir string, segments []string, method string) string {
+	names := []string{segments[0], "_"}
+	if len(segments) == 1 {
+		for _, name := range names {
+			files := []string{name + "." + method + ".json"}
+			if method == "get" || method == "head" {
+				files = append(files, name+".json")
+			}
+			for _, file := range files {
+				p := filepath.Join(dir, file)
+				if info, err := os.Stat(p); err == nil && !info.IsDir() {
+					return p
+				}
+			}
+		}
+		return ""
+	}
+	for _, name := range names {
+		sub := filepath.Join(dir, name)
+		if info, err := os.Stat(sub); err != nil || !info.IsDir() {
+			continue
+		}
+		if f := m.find(sub, segments[1:], method); f != "" {
+			return f
+		}
+	}
+	return ""
+}
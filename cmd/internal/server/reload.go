@@ -0,0 +1,143 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// reloadPath is the server-sent events endpoint pages subscribe to for live
+// reload.
+const reloadPath = "/_greact/reload"
+
+// reloadScript reloads the page when the server sends a reload event and shows
+// errors of build-error events in an overlay, which goes away with the reload
+// that follows the next successful build. The browser reconnects on its own
+// when the server restarts.
+const reloadScript = `<script>
+(function() {
+	var events = new EventSource("` + reloadPath + `");
+	events.addEventListener("reload", function() {
+		location.reload();
+	});
+	events.addEventListener("build-error", function(e) {
+		var el = document.getElementById("greact-overlay");
+		if (!el) {
+			el = document.createElement("pre");
+			el.id = "greact-overlay";
+			el.style.cssText = "position:fixed;top:0;left:0;right:0;bottom:0;z-index:2147483647;" +
+				"margin:0;padding:2em;overflow:auto;background:rgba(0,0,0,0.9);color:#e8e8e8;" +
+				"font:14px/1.5 monospace;white-space:pre-wrap";
+			document.body.appendChild(el);
+		}
+		el.textContent = "build failed\n\n" + e.data;
+	});
+})();
+</script>
+`
+
+// broker sends events to connected browsers. The last build error is kept
+// until it is cleared and sent to browsers when they connect, so pages loaded
+// while the build is broken show the errors too.
+type broker struct {
+	mu      sync.Mutex
+	clients map[chan event]struct{}
+	err     *event
+}
+
+type event struct {
+	name, data string
+}
+
+func newBroker() *broker {
+	return &broker{clients: make(map[chan event]struct{})}
+}
+
+func (b *broker) subscribe() chan event {
+	ch := make(chan event, 1)
+	b.mu.Lock()
+	b.clients[ch] = struct{}{}
+	if b.err != nil {
+		ch <- *b.err
+	}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *broker) unsubscribe(ch chan event) {
+	b.mu.Lock()
+	delete(b.clients, ch)
+	b.mu.Unlock()
+}
+
+// publish sends e to all clients. Clients that have not read the previous
+// event are skipped, they are about to reload anyway.
+func (b *broker) publish(name, data string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.clients {
+		select {
+		case ch <- event{name: name, data: data}:
+		default:
+		}
+	}
+}
+
+// setError publishes a build-error event with msg and keeps it for browsers
+// that connect later. An empty msg clears the error.
+func (b *broker) setError(msg string) {
+	b.mu.Lock()
+	if msg == "" {
+		b.err = nil
+		b.mu.Unlock()
+		return
+	}
+	b.err = &event{name: "build-error", data: msg}
+	b.mu.Unlock()
+	b.publish("build-error", msg)
+}
+
+func (b *broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	ch := b.subscribe()
+	defer b.unsubscribe(ch)
+	// Comments are ignored by EventSource, this lets the client know the
+	// connection is established.
+	fmt.Fprint(w, ": connected\n\n")
+	f.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-ch:
+			fmt.Fprintf(w, "event: %s\n", e.name)
+			for _, line := range strings.Split(e.data, "\n") {
+				fmt.Fprintf(w, "data: %s\n", line)
+			}
+			fmt.Fprint(w, "\n")
+			f.Flush()
+		}
+	}
+}
+
+// injectScript returns page with script inserted before the closing body tag,
+// or appended when there is none.
+func injectScript(page []byte, script string) []byte {
+	i := bytes.LastIndex(bytes.ToLower(page), []byte("</body>"))
+	if i == -1 {
+		return append(append([]byte(nil), page...), script...)
+	}
+	var buf bytes.Buffer
+	buf.Write(page[:i])
+	buf.WriteString(script)
+	buf.Write(page[i:])
+	return buf.Bytes()
+}
@@ -1,21 +1,79 @@
 package server
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
-	"net/http/httputil"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/gernest/greact/cmd/internal/gen"
+	"github.com/gernest/greact/cmd/internal/watch"
 	"github.com/urfave/cli"
 )
 
 // Serve defines serve command which builds and serves wasm modules.
 func Serve() cli.Command {
 	return cli.Command{
-		Name:   "serve",
-		Usage:  "builds and starts web server to serve wasm modules",
+		Name:  "serve",
+		Usage: "builds and starts web server to serve wasm modules",
+		Description: `The package in the given directory, or the current directory, is built to
+   main.wasm and served with an index page that runs it.
+
+   Unless --reload=false is given, the package and its sub directories are
+   watched. On changes render files are regenerated, main.wasm is rebuilt and
+   open pages reload. When the build fails the server keeps running and open
+   pages show the errors, errors in generated render files point to the
+   templates they come from.
+
+   Settings can be kept in a ` + configName + ` file in the package directory,
+   flags that are given override them.
+
+	{
+		"addr": ":8080",
+		"tags": "dev",
+		"ldflags": "-s -w",
+		"trimpath": true,
+		"out": "build",
+		"static": "public",
+		"shell": "shell.html",
+		"title": "App"
+	}
+
+   Paths in the file are relative to it. Files in the static directory are
+   served from the root, changes to them reload open pages. Other page loads,
+   like /users/42, get the index page so apps can route on the client while
+   missing assets are not found. Requests are logged to stdout.
+
+   With --proxy /api=http://localhost:8080 requests for /api and paths below
+   it, web sockets included, are sent to the given server. The flag can be
+   repeated. With --mock dir requests are answered from json fixtures when
+   there is one for the path, GET /api/users/42 reads api/users/42.json and
+   POST reads api/users/42.post.json, fixtures without a method only answer
+   GET and HEAD. A directory or file named _ matches any path segment. The
+   index page, main.wasm, wasm_exec.js and static files are never mocked, and
+   fixtures come before proxies.
+
+   The index page is the shell, an html/template executed with .Title, .Wasm
+   and .WasmExec, the urls of main.wasm and of the wasm_exec.js that loads it,
+   and .Content which holds server rendered html when there is any.
+   wasm_exec.js is served from the GOROOT of the go command that builds
+   main.wasm so the two always match.`,
+		Flags: append(configFlags(),
+			cli.BoolTFlag{
+				Name:  "reload",
+				Usage: "rebuild on changes and reload open pages",
+			},
+			cli.DurationFlag{
+				Name:  "interval",
+				Usage: "how often files are checked for changes",
+				Value: watch.DefaultInterval,
+			},
+		),
 		Action: serve,
 	}
 }
@@ -29,39 +87,250 @@ func serve(ctx *cli.Context) error {
 		}
 		a = wd
 	}
-	out := filepath.Join(a, "main.wasm")
-	cmd := exec.Command("go", "build", "-o", out, a)
-	cmd.Env = os.Environ()
-	cmd.Env = append(cmd.Env, "GOARCH=wasm")
-	cmd.Env = append(cmd.Env, "GOOS=js")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stdout
-	err := cmd.Run()
+	a, err := filepath.Abs(a)
+	if err != nil {
+		return err
+	}
+	c, err := loadConfig(ctx, a)
+	if err != nil {
+		return err
+	}
+	// The shell is read on every request so changes show up on reload, it is
+	// checked here so mistakes are reported early.
+	if _, err := parseShell(c.Shell); err != nil {
+		return err
+	}
+	js, err := wasmExec()
 	if err != nil {
 		return err
 	}
-	idx := "cmd/server/index.html"
-	v, err := Asset(idx)
+	var events *broker
+	if ctx.BoolT("reload") {
+		dirs, err := gen.PackageDirs(filepath.Join(a, "..."))
+		if err != nil {
+			return err
+		}
+		events = newBroker()
+		// Build errors are shown in the page, the server keeps running so
+		// they can be fixed.
+		rebuild(a, dirs, c, events)
+		interval := ctx.Duration("interval")
+		go watchPackage(a, dirs, c, interval, events)
+		if c.Static != "" {
+			go watchStatic(c.Static, interval, events)
+		}
+	} else if err := build(a, c); err != nil {
+		return err
+	}
+	h, err := handler(c, js, events)
 	if err != nil {
 		return err
 	}
-	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	fmt.Printf("serving main.wasm from http://%s\n", displayAddr(c.Addr))
+	return http.ListenAndServe(c.Addr, accessLog(os.Stdout, h))
+}
+
+// handler serves the index page, the module built with c, wasm_exec.js from
+// the path js and static files. Navigation requests for other paths get the
+// index page too so apps can route on the client, missing assets are not
+// found. Mock fixtures and proxy rules of c come after the index page, the
+// module, wasm_exec.js and static files but before navigation requests. Pages
+// get the reload script unless events is nil.
+func handler(c *config, js string, events *broker) (http.Handler, error) {
+	rules, err := newProxyRules(c.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	var mock *mocks
+	if c.Mock != "" {
+		mock = &mocks{dir: c.Mock}
+	}
+	shell := func(w http.ResponseWriter, r *http.Request) {
+		v, err := renderShell(c.Shell, shellData{
+			Title:    c.Title,
+			Wasm:     "/main.wasm",
+			WasmExec: "/wasm_exec.js",
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if events != nil {
+			v = injectScript(v, reloadScript)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(v)
+	}
+	var static http.Handler
+	if c.Static != "" {
+		static = http.FileServer(http.Dir(c.Static))
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case "/":
-			w.Write(v)
+			shell(w, r)
+			return
 		case "/main.wasm":
-			http.ServeFile(w, r, filepath.Join(a, "main.wasm"))
-		default:
-			v, err := httputil.DumpRequest(r, true)
+			http.ServeFile(w, r, filepath.Join(c.Out, "main.wasm"))
+			return
+		case "/wasm_exec.js":
+			http.ServeFile(w, r, js)
+			return
+		case reloadPath:
+			if events == nil {
+				http.NotFound(w, r)
+				return
+			}
+			events.ServeHTTP(w, r)
+			return
+		}
+		if static != nil && isFile(c.Static, r.URL.Path) {
+			static.ServeHTTP(w, r)
+			return
+		}
+		if mock != nil && mock.serve(w, r) {
+			return
+		}
+		for _, rule := range rules {
+			if rule.match(r.URL.Path) {
+				rule.proxy.ServeHTTP(w, r)
+				return
+			}
+		}
+		if isNavigation(r) {
+			shell(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	}), nil
+}
+
+// isFile returns true if name is a regular file in dir.
+func isFile(dir, name string) bool {
+	info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path.Clean("/"+name))))
+	return err == nil && !info.IsDir()
+}
+
+// isNavigation returns true if r is a browser loading a page, not an asset.
+// Paths with an extension are taken to be assets even when html is accepted.
+func isNavigation(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if path.Ext(r.URL.Path) != "" {
+		return false
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// displayAddr returns addr in a form that can be opened in a browser.
+func displayAddr(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		return "localhost" + addr
+	}
+	return addr
+}
+
+// build compiles the package in dir with c to main.wasm in the output
+// directory. The module is written to a temporary file first so a failed
+// build keeps serving the previous one. Compiler errors are returned as
+// *buildError.
+func build(dir string, c *config) error {
+	if err := os.MkdirAll(c.Out, 0755); err != nil {
+		return err
+	}
+	out := filepath.Join(c.Out, "main.wasm")
+	tmp := out + ".tmp"
+	var output bytes.Buffer
+	cmd := exec.Command("go", c.buildArgs(tmp)...)
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	cmd.Env = append(cmd.Env, "GOARCH=wasm")
+	cmd.Env = append(cmd.Env, "GOOS=js")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		os.Remove(tmp)
+		return &buildError{Dir: dir, Output: output.String(), Err: err}
+	}
+	return os.Rename(tmp, out)
+}
+
+func renderDirs(dirs []string, tags []string) error {
+	for _, dir := range dirs {
+		if err := gen.RenderPackage(dir, tags...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchPackage rebuilds the package in dir when files in dirs, or in
+// directories of their templates, change and tells open pages to reload. On
+// errors the last good build is kept.
+func watchPackage(dir string, dirs []string, c *config, interval time.Duration, events *broker) {
+	templates := make(map[string][]string)
+	watched := func() []string {
+		seen := make(map[string]bool)
+		var w []string
+		for _, d := range dirs {
+			t, err := gen.TemplateDirs(d, c.buildTags()...)
 			if err != nil {
-				fmt.Printf("error: %v\n", err)
-			} else {
-				fmt.Println(string(v))
-				w.Write(v)
+				// keep watching them while a template doesn't parse.
+				t = templates[d]
+			}
+			templates[d] = t
+			for _, v := range append([]string{d}, t...) {
+				if !seen[v] {
+					seen[v] = true
+					w = append(w, v)
+				}
 			}
 		}
+		return w
+	}
+	w := watch.New(gen.IsRenderSource, watched()...)
+	w.Interval = interval
+	fmt.Printf("serve: watching %d packages for changes\n", len(dirs))
+	w.Run(nil, func(changed []string) {
+		fmt.Printf("serve: %s changed, rebuilding\n", filepath.Base(changed[0]))
+		if rebuild(dir, dirs, c, events) {
+			events.publish("reload", "")
+		}
+		w.Dirs = watched()
 	})
-	msg := fmt.Sprint("serving main.wasm from http://localhost:8099")
-	fmt.Println(msg)
-	return http.ListenAndServe(":8099", h)
+}
+
+// watchStatic tells open pages to reload when files in the static directory
+// or its sub directories change.
+func watchStatic(static string, interval time.Duration, events *broker) {
+	var dirs []string
+	filepath.Walk(static, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	w := watch.New(nil, dirs...)
+	w.Interval = interval
+	w.Run(nil, func(changed []string) {
+		fmt.Printf("serve: %s changed, reloading\n", filepath.Base(changed[0]))
+		events.publish("reload", "")
+	})
+}
+
+// rebuild renders dirs and builds the package in dir. Errors are printed and
+// sent to open pages as a build-error event, false is returned in that case.
+func rebuild(dir string, dirs []string, c *config, events *broker) bool {
+	err := renderDirs(dirs, c.buildTags())
+	if err == nil {
+		err = build(dir, c)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		events.setError(err.Error())
+		return false
+	}
+	events.setError("")
+	return true
 }
@@ -0,0 +1,765 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gernest/greact/cmd/internal/gen"
+	"github.com/gernest/greact/cmd/internal/wasm"
+	"github.com/urfave/cli"
+)
+
+func TestInjectScript(t *testing.T) {
+	sample := []struct {
+		page, expect string
+	}{
+		{"<html><body><p></p></BODY></html>", "<html><body><p></p><s></BODY></html>"},
+		{"<p></p>", "<p></p><s>"},
+	}
+	for _, v := range sample {
+		got := string(injectScript([]byte(v.page), "<s>"))
+		if got != v.expect {
+			t.Errorf("expected %s got %s", v.expect, got)
+		}
+	}
+}
+
+func TestReloadEvents(t *testing.T) {
+	b := newBroker()
+	ts := httptest.NewServer(b)
+	defer ts.Close()
+	res, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream got %s", ct)
+	}
+	r := bufio.NewReader(res.Body)
+	readEvent := func() string {
+		var lines []string
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+			if line == "\n" {
+				return strings.Join(lines, "")
+			}
+			lines = append(lines, line)
+		}
+	}
+	if e := readEvent(); e != ": connected\n" {
+		t.Fatalf("expected connected comment got %q", e)
+	}
+	b.publish("reload", "two\nlines")
+	expect := "event: reload\ndata: two\ndata: lines\n"
+	if e := readEvent(); e != expect {
+		t.Errorf("expected %q got %q", expect, e)
+	}
+}
+
+func TestBuildErrorEvent(t *testing.T) {
+	b := newBroker()
+	b.setError("broken")
+	ch := b.subscribe()
+	defer b.unsubscribe(ch)
+	select {
+	case e := <-ch:
+		if e.name != "build-error" || e.data != "broken" {
+			t.Errorf("expected the last build error got %v", e)
+		}
+	default:
+		t.Fatal("expected new clients to get the last build error")
+	}
+	b.publish("reload", "")
+	<-ch
+	ch3 := b.subscribe()
+	b.unsubscribe(ch3)
+	if len(ch3) != 1 {
+		t.Error("expected reload events to keep the build error")
+	}
+	b.setError("")
+	ch2 := b.subscribe()
+	defer b.unsubscribe(ch2)
+	select {
+	case e := <-ch2:
+		t.Errorf("expected no event after the error is cleared got %v", e)
+	default:
+	}
+}
+
+func TestMapErrors(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := `package app
+
+import "github.com/gernest/greact"
+
+type Hello struct {
+	greact.Core
+	Name string
+}
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "hello.go"), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "hello.html"), []byte(`<p>{h.Name}</p>`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.RenderPackage(dir); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "app_render_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	line, col := 0, 0
+	for i, v := range strings.Split(string(b), "\n") {
+		if j := strings.Index(v, "h.Name"); j != -1 {
+			line, col = i+1, j+1
+			break
+		}
+	}
+	output := fmt.Sprintf(`# app
+./app_render_gen.go:%d:%d: undefined: x
+./hello.go:3:8: imported and not used`, line, col)
+	expect := fmt.Sprintf(`# app
+hello.html:1:4: Hello: undefined: x (generated app_render_gen.go:%d:%d)
+./hello.go:3:8: imported and not used`, line, col)
+	if got := mapErrors(dir, output); got != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, got)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "greact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Mkdir(filepath.Join(dir, "public"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := `{"addr": ":8080", "tags": "dev, debug", "trimpath": true, "out": "build", "static": "public"}`
+	if err := ioutil.WriteFile(filepath.Join(dir, configName), []byte(cfg), 0600); err != nil {
+		t.Fatal(err)
+	}
+	load := func(args ...string) *config {
+		var c *config
+		app := cli.NewApp()
+		app.Flags = configFlags()
+		app.Action = func(ctx *cli.Context) error {
+			var err error
+			c, err = loadConfig(ctx, dir)
+			return err
+		}
+		if err := app.Run(append([]string{"serve"}, args...)); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	c := load()
+	expect := &config{
+		Addr:     ":8080",
+		Tags:     "dev, debug",
+		Trimpath: true,
+		Out:      filepath.Join(dir, "build"),
+		Static:   filepath.Join(dir, "public"),
+		Title:    filepath.Base(dir),
+		Dist:     filepath.Join(dir, "dist"),
+	}
+	if !reflect.DeepEqual(c, expect) {
+		t.Errorf("expected %+v got %+v", expect, c)
+	}
+	if tags := c.buildTags(); !reflect.DeepEqual(tags, []string{"dev", "debug"}) {
+		t.Errorf("expected dev and debug tags got %v", tags)
+	}
+	c = load("--addr", "127.0.0.1:9000", "--ldflags", "-s -w", "--trimpath=false", "--proxy", "/api=http://localhost:8080")
+	expectArgs := []string{"build", "-o", "main.wasm", "-tags", "dev, debug", "-ldflags", "-s -w", "."}
+	if args := c.buildArgs("main.wasm"); !reflect.DeepEqual(args, expectArgs) {
+		t.Errorf("expected %v got %v", expectArgs, args)
+	}
+	if c.Addr != "127.0.0.1:9000" {
+		t.Errorf("expected the addr flag to override the config got %s", c.Addr)
+	}
+	if p := c.Proxy["/api"]; p != "http://localhost:8080" {
+		t.Errorf("expected /api to be proxied to http://localhost:8080 got %q", p)
+	}
+}
+
+func TestStaticFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "greact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "style.css"), []byte("p{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "wasm_exec.js"), []byte("go"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "shell.html"), []byte("<title>{{.Title}}</title>{{.WasmExec}}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := &config{Out: dir, Static: dir, Shell: filepath.Join(dir, "shell.html"), Title: "a&b"}
+	var log bytes.Buffer
+	h, err := handler(c, filepath.Join(dir, "wasm_exec.js"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(accessLog(&log, h))
+	defer ts.Close()
+	shell := "<title>a&amp;b</title>/wasm_exec.js"
+	sample := []struct {
+		path, accept string
+		status       int
+		body         string
+	}{
+		{"/", "", http.StatusOK, shell},
+		{"/style.css", "", http.StatusOK, "p{}"},
+		{"/wasm_exec.js", "", http.StatusOK, "go"},
+		{"/users/42", "text/html,application/xhtml+xml", http.StatusOK, shell},
+		{"/users/42", "application/json", http.StatusNotFound, "404 page not found\n"},
+		{"/missing.png", "text/html", http.StatusNotFound, "404 page not found\n"},
+		{reloadPath, "", http.StatusNotFound, "404 page not found\n"},
+	}
+	for _, v := range sample {
+		req, err := http.NewRequest("GET", ts.URL+v.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.accept != "" {
+			req.Header.Set("Accept", v.accept)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != v.status {
+			t.Errorf("%s: expected status %d got %d", v.path, v.status, res.StatusCode)
+		}
+		if string(b) != v.body {
+			t.Errorf("%s: expected %q got %q", v.path, v.body, b)
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != len(sample) {
+		t.Fatalf("expected %d log lines got %d", len(sample), len(lines))
+	}
+	expect := `serve: method=GET path="/missing.png" status=404 bytes=19 duration=`
+	if !strings.HasPrefix(lines[5], expect) {
+		t.Errorf("expected %s got %s", expect, lines[5])
+	}
+}
+
+func TestDefaultShell(t *testing.T) {
+	b, err := renderShell("", shellData{Title: "app", Wasm: "/main.wasm", WasmExec: "/wasm_exec.js"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"<title>app</title>", `src="/wasm_exec.js"`, `fetch("\/main.wasm")`} {
+		if !strings.Contains(string(b), v) {
+			t.Errorf("expected %s in\n%s", v, b)
+		}
+	}
+	if _, err := wasmExec(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") == "websocket" {
+			conn, rw, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer conn.Close()
+			rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+			rw.Flush()
+			line, _ := rw.ReadString('\n')
+			rw.WriteString("echo " + line)
+			rw.Flush()
+			return
+		}
+		fmt.Fprintf(w, "%s %s", r.Host, r.URL.Path)
+	}))
+	defer backend.Close()
+	dir, err := ioutil.TempDir("", "greact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, data := range map[string]string{
+		"api/users.json":          `[]`,
+		"api/users/_.json":        `{"id": 0}`,
+		"api/users/42.json":       `{"id": 42}`,
+		"api/users/42.post.json":  `{"created": true}`,
+		"api/_/settings.get.json": `{"dark": true}`,
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := &config{
+		Out:   dir,
+		Mock:  dir,
+		Proxy: map[string]string{"/api": backend.URL, "/api/v2/": backend.URL + "/v2"},
+	}
+	h, err := handler(c, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the access log wraps the handler in serve, upgrades must get through it.
+	ts := httptest.NewServer(accessLog(ioutil.Discard, h))
+	defer ts.Close()
+	host := strings.TrimPrefix(backend.URL, "http://")
+	sample := []struct {
+		method, path, expect string
+	}{
+		{"GET", "/api/users", `[]`},
+		{"GET", "/api/users/42", `{"id": 42}`},
+		{"POST", "/api/users/42", `{"created": true}`},
+		{"GET", "/api/users/7", `{"id": 0}`},
+		{"GET", "/api/teams/settings", `{"dark": true}`},
+		{"POST", "/api/teams/settings", host + " /api/teams/settings"},
+		{"GET", "/api/v2/items", host + " /v2/api/v2/items"},
+		{"GET", "/apiary", "404 page not found\n"},
+		{"HEAD", "/api/users/42", ""},
+		{"DELETE", "/api/users/42", host + " /api/users/42"},
+	}
+	for _, v := range sample {
+		req, err := http.NewRequest(v.method, ts.URL+v.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != v.expect {
+			t.Errorf("%s %s: expected %q got %q", v.method, v.path, v.expect, b)
+		}
+	}
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	fmt.Fprint(conn, "GET /api/socket HTTP/1.1\r\nHost: localhost\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+	r := bufio.NewReader(conn)
+	res, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101 got %s", res.Status)
+	}
+	fmt.Fprint(conn, "ping\n")
+	line, err := r.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line != "echo ping\n" {
+		t.Errorf("expected echo ping got %q", line)
+	}
+}
+
+func TestMocksShadow(t *testing.T) {
+	dir, err := ioutil.TempDir("", "greact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, data := range map[string]string{
+		"mock/_.json":    `{"mock": true}`,
+		"out/main.wasm":  "wasm",
+		"wasm_exec.js":   "exec",
+		"static/app.css": "css",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := &config{
+		Out:    filepath.Join(dir, "out"),
+		Mock:   filepath.Join(dir, "mock"),
+		Static: filepath.Join(dir, "static"),
+		Title:  "app",
+	}
+	h, err := handler(c, filepath.Join(dir, "wasm_exec.js"), newBroker())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sample := map[string]string{
+		"/main.wasm":    "wasm",
+		"/wasm_exec.js": "exec",
+		"/app.css":      "css",
+		"/users":        `{"mock": true}`,
+	}
+	for path, expect := range sample {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Body.String() != expect {
+			t.Errorf("%s: expected %q got %q", path, expect, w.Body.String())
+		}
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if !strings.Contains(w.Body.String(), "<title>app</title>") {
+		t.Errorf("expected the index page got %s", w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/users", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected a fixture without method to only answer GET and HEAD, got %d", w.Code)
+	}
+}
+
+func TestProxyRules(t *testing.T) {
+	for _, v := range []map[string]string{
+		{"api": "http://localhost"},
+		{"/api": "localhost:8080"},
+		{"/api": "ftp://localhost"},
+	} {
+		if _, err := newProxyRules(v); err == nil {
+			t.Errorf("expected %v to be invalid", v)
+		}
+	}
+	if _, _, err := parseProxy("/api"); err == nil {
+		t.Error("expected an error for a rule without url")
+	}
+	prefix, target, err := parseProxy("/api=http://localhost:8080/?a=b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prefix != "/api" || target != "http://localhost:8080/?a=b" {
+		t.Errorf("expected /api and http://localhost:8080/?a=b got %s and %s", prefix, target)
+	}
+}
+
+func TestBundle(t *testing.T) {
+	static, err := ioutil.TempDir("", "greact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(static)
+	dist, err := ioutil.TempDir("", "greact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dist)
+	css := strings.Repeat("p { color: red; }\n", 100)
+	for name, data := range map[string]string{
+		"css/app.css": css,
+		"favicon.ico": "icon",
+		"about.html":  "<p>about</p>",
+		"index.html":  "ignored",
+	} {
+		p := filepath.Join(static, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b := &bundle{dir: dist, manifest: make(map[string]string)}
+	if err := b.addStatic(static); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.compress(); err != nil {
+		t.Fatal(err)
+	}
+	hashed := hashName("css/app.css", []byte(css))
+	if !strings.HasPrefix(hashed, "css/app.") || !strings.HasSuffix(hashed, ".css") || len(hashed) != len("css/app.css")+11 {
+		t.Errorf("unexpected hashed name %s", hashed)
+	}
+	expect := map[string]string{"css/app.css": hashed}
+	if !reflect.DeepEqual(b.manifest, expect) {
+		t.Errorf("expected manifest %v got %v", expect, b.manifest)
+	}
+	var files []string
+	filepath.Walk(dist, func(p string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			rel, _ := filepath.Rel(dist, p)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	expectFiles := []string{"about.html", hashed, hashed + ".br", hashed + ".gz", "favicon.ico"}
+	if !reflect.DeepEqual(files, expectFiles) {
+		t.Errorf("expected files %v got %v", expectFiles, files)
+	}
+	br, err := ioutil.ReadFile(filepath.Join(dist, filepath.FromSlash(hashed+".br")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(brotli.NewReader(bytes.NewReader(br)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != css {
+		t.Error("expected the brotli file to decompress to the original")
+	}
+
+	shell := filepath.Join(static, "..", filepath.Base(static)+".html")
+	if err := ioutil.WriteFile(shell, []byte(`<link rel="stylesheet" href="{{asset "/css/app.css"}}">`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(shell)
+	c := &config{Shell: shell}
+	if err := b.writePages(c, "main.wasm", "wasm_exec.js", nil); err != nil {
+		t.Fatal(err)
+	}
+	index, err := ioutil.ReadFile(filepath.Join(dist, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectIndex := `<link rel="stylesheet" href="/` + hashed + `">`
+	if string(index) != expectIndex {
+		t.Errorf("expected %s got %s", expectIndex, index)
+	}
+	if _, err := os.Stat(filepath.Join(dist, filepath.FromSlash(hashed))); err != nil {
+		t.Errorf("expected the stylesheet link to resolve: %v", err)
+	}
+	served, err := renderShell(shell, shellData{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := `<link rel="stylesheet" href="/css/app.css">`; string(served) != expect {
+		t.Errorf("expected %s when serving got %s", expect, served)
+	}
+	if err := ioutil.WriteFile(shell, []byte(`{{asset "/missing.css"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.writePages(c, "main.wasm", "wasm_exec.js", nil); err == nil {
+		t.Error("expected an error for an asset that is not in the bundle")
+	}
+}
+
+func TestCheckDist(t *testing.T) {
+	sample := []struct {
+		c  config
+		ok bool
+	}{
+		{config{Dist: "/app/dist"}, true},
+		{config{Dist: "/dist"}, true},
+		{config{Dist: "/app"}, false},
+		{config{Dist: "/"}, false},
+		{config{Dist: "/app/dist", Static: "/app/public", Shell: "/app/shell.html", Mock: "/app/mocks"}, true},
+		{config{Dist: "/app/distant", Static: "/app/dist"}, true},
+		{config{Dist: "/app/public", Static: "/app/public"}, false},
+		{config{Dist: "/app/public/dist", Static: "/app/public"}, false},
+		{config{Dist: "/app/www", Static: "/app/www/public"}, false},
+		{config{Dist: "/app/www", Shell: "/app/www/shell.html"}, false},
+		{config{Dist: "/app/mocks", Mock: "/app/mocks"}, false},
+	}
+	for _, v := range sample {
+		err := checkDist("/app", &v.c)
+		if (err == nil) != v.ok {
+			t.Errorf("%+v: expected ok=%v got %v", v.c, v.ok, err)
+		}
+	}
+}
+
+func TestPackageMatcher(t *testing.T) {
+	match := packageMatcher([]string{"fmt", "github.com/gernest/greact/expr", "github.com/gernest/greact", "main"})
+	sample := map[string]string{
+		"fmt.__pp_.printArg":                     "fmt",
+		"fmt.(*pp).printArg":                     "fmt",
+		"github.com_gernest_greact_expr.toValue": "github.com/gernest/greact/expr",
+		"github.com/gernest/greact/expr.toValue": "github.com/gernest/greact/expr",
+		"github.com_gernest_greact.Core.Props":   "github.com/gernest/greact",
+		"main.main":                              "main",
+		"fmtx.Print":                             otherPackage,
+		"_rt0_wasm_js":                           otherPackage,
+	}
+	for name, expect := range sample {
+		if got := match(name); got != expect {
+			t.Errorf("%s: expected %s got %s", name, expect, got)
+		}
+	}
+}
+
+func TestSizeCompare(t *testing.T) {
+	m := &wasm.Module{
+		Sections: []wasm.Section{{ID: 10, Name: "code", Size: 110}, {ID: 11, Name: "data", Size: 40}},
+		Funcs: []wasm.Func{
+			{Index: 0, Name: "fmt.Println", Size: 60},
+			{Index: 1, Name: "main.main", Size: 50},
+		},
+	}
+	r := newSizeReport(m, []string{"fmt", "main"})
+	expect := map[string]int{"fmt": 60, "main": 50}
+	if r.Total != 150 || !reflect.DeepEqual(r.Packages, expect) {
+		t.Fatalf("expected total 150 and %v got %d and %v", expect, r.Total, r.Packages)
+	}
+	dir, err := ioutil.TempDir("", "greact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "size.json")
+	base := &sizeReport{Total: 100, Packages: map[string]int{"main": 50, "strconv": 10}}
+	if err := base.save(path); err != nil {
+		t.Fatal(err)
+	}
+	base, err = loadSizeReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w bytes.Buffer
+	err = r.compare(&w, base, 10, 10)
+	if err == nil {
+		t.Fatal("expected 50% growth to fail")
+	}
+	expectErr := "module grew by 50 bytes (50.0%) over the baseline, at most 10.0% is allowed"
+	if err.Error() != expectErr {
+		t.Errorf("expected %s got %v", expectErr, err)
+	}
+	for _, v := range []string{"fmt      0B        60B   +60", "strconv  10B       0B    -10"} {
+		if !strings.Contains(w.String(), v) {
+			t.Errorf("expected %q in\n%s", v, w.String())
+		}
+	}
+	if err := r.compare(ioutil.Discard, base, 10, 50); err != nil {
+		t.Errorf("expected 50%% growth to be allowed got %v", err)
+	}
+	if err := r.compare(ioutil.Discard, base, 10, 60); err != nil {
+		t.Errorf("expected growth under 60%% to be allowed got %v", err)
+	}
+
+	// By default no growth is allowed.
+	if err := r.compare(ioutil.Discard, base, 10, 0); err == nil {
+		t.Error("expected growth to fail with no allowance")
+	}
+	if err := r.compare(ioutil.Discard, r, 10, 0); err != nil {
+		t.Errorf("expected the same size to be allowed got %v", err)
+	}
+	if err := base.compare(ioutil.Discard, r, 10, 0); err != nil {
+		t.Errorf("expected a smaller module to be allowed got %v", err)
+	}
+}
+
+func TestRenderPages(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := `package pages
+
+import (
+	"context"
+
+	"github.com/gernest/greact/node"
+)
+
+type Home struct{}
+
+func (h *Home) Render(ctx context.Context, props node.Props, state node.State) *node.Node {
+	return node.New(node.ElementNode, "", "h1", nil, node.New(node.TextNode, "", "home", nil))
+}
+
+type Docs struct{}
+
+func (d *Docs) Render(ctx context.Context, props node.Props, state node.State) *node.Node {
+	return node.New(node.ElementNode, "", "p", nil, node.New(node.TextNode, "", "docs", nil))
+}
+
+var Routes = map[string]node.Component{
+	"/404.html": &Docs{},
+}
+`
+	if err := os.Mkdir(filepath.Join(dir, "pages"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "pages", "pages.go"), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := &config{
+		Routes:     map[string]string{"/": "./pages.Home", "/docs": "./pages.Docs"},
+		RouteTable: "./pages.Routes",
+	}
+	pages, err := renderPages(dir, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{
+		"/":         "<h1>home</h1>",
+		"/docs":     "<p>docs</p>",
+		"/404.html": "<p>docs</p>",
+	}
+	if !reflect.DeepEqual(pages, expect) {
+		t.Errorf("expected %v got %v", expect, pages)
+	}
+	if _, err := os.Stat(filepath.Join(dir, hostDir)); !os.IsNotExist(err) {
+		t.Error("expected the host program to be removed")
+	}
+	c.Routes = map[string]string{"/": "./pages.home-page"}
+	if _, err := renderPages(dir, c); err == nil {
+		t.Error("expected an error for an invalid component name")
+	}
+}
+
+func TestPageFile(t *testing.T) {
+	sample := map[string]string{
+		"/":          "index.html",
+		"/docs":      "docs/index.html",
+		"/docs/":     "docs/index.html",
+		"/a/b":       "a/b/index.html",
+		"/404.html":  "404.html",
+		"/../escape": "escape/index.html",
+	}
+	for route, expect := range sample {
+		if got := pageFile(route); got != expect {
+			t.Errorf("%s: expected %s got %s", route, expect, got)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// shellData is what shell templates are executed with.
+type shellData struct {
+	// Title is the page title.
+	Title string
+
+	// Wasm and WasmExec are urls of the module and of wasm_exec.js which
+	// loads it.
+	Wasm     string
+	WasmExec string
+
+	// Content is html rendered on the server, it is empty when serving.
+	Content template.HTML
+
+	// Assets maps names of static files to their hashed names in the bundle,
+	// it is nil when serving. Shells link static files with the asset func,
+	// like {{asset "/style.css"}}, so the links resolve in both cases.
+	Assets map[string]string
+}
+
+// asset returns the url of the static file name. Names are returned as they
+// are when serving, in a bundle they must be in Assets.
+func (d shellData) asset(name string) (string, error) {
+	if d.Assets == nil {
+		return name, nil
+	}
+	hashed, ok := d.Assets[strings.TrimPrefix(name, "/")]
+	if !ok {
+		return "", fmt.Errorf("asset %q is not in the static directory", name)
+	}
+	if strings.HasPrefix(name, "/") {
+		return "/" + hashed, nil
+	}
+	return hashed, nil
+}
+
+// defaultShell is the page used when the config has no shell template.
+const defaultShell = `<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{.Title}}</title>
+	<script src="{{.WasmExec}}"></script>
+	<script>
+		var go = new Go();
+		WebAssembly.instantiateStreaming(fetch("{{.Wasm}}"), go.importObject).then(function(result) {
+			go.run(result.instance);
+		});
+	</script>
+</head>
+<body>
+	<div id="main">{{.Content}}</div>
+</body>
+</html>
+`
+
+// parseShell parses the shell template at path, the default shell is returned
+// when path is empty.
+func parseShell(path string) (*template.Template, error) {
+	funcs := template.FuncMap{"asset": shellData{}.asset}
+	if path == "" {
+		return template.New("shell").Funcs(funcs).Parse(defaultShell)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New(filepath.Base(path)).Funcs(funcs).Parse(string(b))
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// renderShell executes the shell template at path with data.
+func renderShell(path string, data shellData) ([]byte, error) {
+	t, err := parseShell(path)
+	if err != nil {
+		return nil, err
+	}
+	t.Funcs(template.FuncMap{"asset": data.asset})
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// wasmExec returns the path of wasm_exec.js of the go toolchain that builds
+// modules, using it keeps the loader in sync with the module. Newer releases
+// keep it in lib/wasm and older ones in misc/wasm.
+func wasmExec() (string, error) {
+	out, err := exec.Command("go", "env", "GOROOT").Output()
+	if err != nil {
+		return "", fmt.Errorf("go env GOROOT: %v", err)
+	}
+	root := strings.TrimSpace(string(out))
+	for _, dir := range []string{"lib", "misc"} {
+		path := filepath.Join(root, dir, "wasm", "wasm_exec.js")
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("wasm_exec.js not found in %s", root)
+}
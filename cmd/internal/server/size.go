@@ -0,0 +1,329 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gernest/greact/cmd/internal/wasm"
+	"github.com/urfave/cli"
+)
+
+// Size defines the size command which reports what makes the wasm module of
+// an app big.
+func Size() cli.Command {
+	return cli.Command{
+		Name:      "size",
+		Usage:     "reports the size of the wasm module by section, package and function",
+		ArgsUsage: "[dir]",
+		Description: `The package in the given directory, or the current directory, is built and
+   the sizes of the sections of the module are printed along with the
+   packages and functions that take the most space in the code section.
+   Function names come from the name section of the module, which the go
+   linker leaves out with -ldflags=-s.
+
+   With --save the report is written as json so a later run can compare with
+   it using --baseline. The command fails when the module grew more than
+   --max-growth percent over the baseline, which makes it usable in CI. The
+   default of 0 allows no growth at all, a module that shrank or kept its size
+   passes.`,
+		Flags: append(buildFlags(),
+			cli.StringFlag{
+				Name:  "wasm",
+				Usage: "report on this module instead of building the package",
+			},
+			cli.IntFlag{
+				Name:  "top",
+				Usage: "number of packages and functions to print",
+				Value: 20,
+			},
+			cli.StringFlag{
+				Name:  "save",
+				Usage: "write the report as json to this file",
+			},
+			cli.StringFlag{
+				Name:  "baseline",
+				Usage: "compare with the report saved in this file",
+			},
+			cli.Float64Flag{
+				Name:  "max-growth",
+				Usage: "percent the module can grow over the baseline before the command fails, 0 allows no growth",
+			},
+		),
+		Action: size,
+	}
+}
+
+func size(ctx *cli.Context) error {
+	a := ctx.Args().First()
+	if a == "" {
+		a = "."
+	}
+	a, err := filepath.Abs(a)
+	if err != nil {
+		return err
+	}
+	c, err := loadConfig(ctx, a)
+	if err != nil {
+		return err
+	}
+	module := ctx.String("wasm")
+	if module == "" {
+		tmp, err := ioutil.TempDir("", "greact-size")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+		bc := *c
+		bc.Out = tmp
+		if err := build(a, &bc); err != nil {
+			return err
+		}
+		module = filepath.Join(tmp, "main.wasm")
+	}
+	b, err := ioutil.ReadFile(module)
+	if err != nil {
+		return err
+	}
+	m, err := wasm.Parse(b)
+	if err != nil {
+		return err
+	}
+	pkgs, err := packageList(a, c)
+	if err != nil {
+		return err
+	}
+	r := newSizeReport(m, pkgs)
+	if r.Unnamed {
+		fmt.Fprintln(os.Stderr, "size: the module has no function names, build it without -ldflags=-s")
+	}
+	if err := r.print(os.Stdout, ctx.Int("top")); err != nil {
+		return err
+	}
+	if p := ctx.String("save"); p != "" {
+		if err := r.save(p); err != nil {
+			return err
+		}
+	}
+	if p := ctx.String("baseline"); p != "" {
+		base, err := loadSizeReport(p)
+		if err != nil {
+			return err
+		}
+		return r.compare(os.Stdout, base, ctx.Int("top"), ctx.Float64("max-growth"))
+	}
+	return nil
+}
+
+// packageList returns import paths of packages the package in dir depends on
+// when built with c. The main package is main, which is how the linker names
+// its functions.
+func packageList(dir string, c *config) ([]string, error) {
+	args := []string{"list", "-deps", "-f", `{{if eq .Name "main"}}main{{else}}{{.ImportPath}}{{end}}`}
+	if c.Tags != "" {
+		args = append(args, "-tags", c.Tags)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", append(args, ".")...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOARCH=wasm", "GOOS=js")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %v\n%s", err, stderr.String())
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// sizeReport is what the size command reports, it is saved as json to
+// compare later builds with.
+type sizeReport struct {
+	Total    int            `json:"total"`
+	Sections map[string]int `json:"sections"`
+	Packages map[string]int `json:"packages"`
+
+	// Unnamed is true when the module has functions but no names for them.
+	Unnamed bool `json:"-"`
+
+	funcs []wasm.Func
+}
+
+// otherPackage is where functions that don't belong to a known package are
+// counted.
+const otherPackage = "(other)"
+
+func newSizeReport(m *wasm.Module, pkgs []string) *sizeReport {
+	r := &sizeReport{
+		Sections: make(map[string]int),
+		Packages: make(map[string]int),
+	}
+	for _, s := range m.Sections {
+		r.Sections[s.Name] += s.Size
+		r.Total += s.Size
+	}
+	match := packageMatcher(pkgs)
+	for _, f := range m.Funcs {
+		r.Packages[match(f.Name)] += f.Size
+	}
+	r.funcs = append(r.funcs, m.Funcs...)
+	sort.SliceStable(r.funcs, func(i, j int) bool {
+		return r.funcs[i].Size > r.funcs[j].Size
+	})
+	r.Unnamed = len(m.Funcs) > 0 && m.Funcs[0].Name == ""
+	return r
+}
+
+// mangle matches characters the go linker replaces with _ in function names
+// of the name section.
+var mangle = regexp.MustCompile(`[^\w.]`)
+
+// packageMatcher returns a function that returns the package in pkgs a
+// function name belongs to. Names are matched as they are and as mangled by
+// newer linkers, longer package paths are tried first.
+func packageMatcher(pkgs []string) func(string) string {
+	type prefix struct {
+		text, pkg string
+	}
+	var prefixes []prefix
+	for _, p := range pkgs {
+		prefixes = append(prefixes, prefix{p + ".", p})
+		if m := mangle.ReplaceAllString(p, "_"); m != p {
+			prefixes = append(prefixes, prefix{m + ".", p})
+		}
+	}
+	sort.SliceStable(prefixes, func(i, j int) bool {
+		return len(prefixes[i].text) > len(prefixes[j].text)
+	})
+	return func(name string) string {
+		for _, p := range prefixes {
+			if strings.HasPrefix(name, p.text) {
+				return p.pkg
+			}
+		}
+		return otherPackage
+	}
+}
+
+// sortedSizes returns keys of m sorted by their values, largest first.
+func sortedSizes(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if m[keys[i]] != m[keys[j]] {
+			return m[keys[i]] > m[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func (r *sizeReport) print(w io.Writer, top int) error {
+	code := r.Sections["code"]
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "total\t%s\t\t\n\n", formatSize(int64(r.Total)))
+	fmt.Fprintln(tw, "section\tsize\tshare\t")
+	for _, name := range sortedSizes(r.Sections) {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t\n", name, formatSize(int64(r.Sections[name])), percent(r.Sections[name], r.Total))
+	}
+	fmt.Fprintf(tw, "\npackage\tcode\tshare\t\n")
+	for i, name := range sortedSizes(r.Packages) {
+		if i == top {
+			break
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t\n", name, formatSize(int64(r.Packages[name])), percent(r.Packages[name], code))
+	}
+	if !r.Unnamed {
+		fmt.Fprintf(tw, "\nfunction\tcode\tshare\t\n")
+		for i, f := range r.funcs {
+			if i == top {
+				break
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t\n", f.Name, formatSize(int64(f.Size)), percent(f.Size, code))
+		}
+	}
+	return tw.Flush()
+}
+
+func percent(n, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(n)*100/float64(total))
+}
+
+func (r *sizeReport) save(path string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+func loadSizeReport(path string) (*sizeReport, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &sizeReport{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return r, nil
+}
+
+// compare prints how r differs from base and returns an error if the total
+// grew more than maxGrowth percent.
+func (r *sizeReport) compare(w io.Writer, base *sizeReport, top int, maxGrowth float64) error {
+	delta := r.Total - base.Total
+	growth := 0.0
+	if base.Total > 0 {
+		growth = float64(delta) * 100 / float64(base.Total)
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "\nbaseline\t%s\t%s\t%+d\t%+.1f%%\t\n", formatSize(int64(base.Total)), formatSize(int64(r.Total)), delta, growth)
+	changes := make(map[string]int)
+	abs := make(map[string]int)
+	for name, n := range r.Packages {
+		if d := n - base.Packages[name]; d != 0 {
+			changes[name] = d
+		}
+	}
+	for name, n := range base.Packages {
+		if _, ok := r.Packages[name]; !ok {
+			changes[name] = -n
+		}
+	}
+	for name, d := range changes {
+		if d < 0 {
+			d = -d
+		}
+		abs[name] = d
+	}
+	if len(changes) > 0 {
+		fmt.Fprintf(tw, "\npackage\tbaseline\tcode\tdelta\t\t\n")
+	}
+	for i, name := range sortedSizes(abs) {
+		if i == top {
+			break
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%+d\t\t\n", name, formatSize(int64(base.Packages[name])), formatSize(int64(r.Packages[name])), changes[name])
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if growth > maxGrowth {
+		return fmt.Errorf("module grew by %d bytes (%.1f%%) over the baseline, at most %.1f%% is allowed", delta, growth, maxGrowth)
+	}
+	return nil
+}
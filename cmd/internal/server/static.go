@@ -0,0 +1,278 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/gernest/greact/cmd/internal/gen"
+	"github.com/urfave/cli"
+)
+
+// hostDir is the directory, in the package directory, the program that
+// renders pages is written to. The leading _ keeps go tools from matching it.
+const hostDir = "_greact_static"
+
+// Static defines the static command which prerenders pages of an app.
+func Static() cli.Command {
+	return cli.Command{
+		Name:      "static",
+		Usage:     "prerenders pages of the app to html",
+		ArgsUsage: "[dir]",
+		Description: `Pages are rendered on the host and written with the bundle of the package in
+   the given directory, or the current directory, like the build command does.
+   The html of a page is the .Content of the shell so it shows before the wasm
+   module loads.
+
+   Routes map paths to components, they are given as --route path=pkg.Type
+   and --routes pkg.Var where Var is a map[string]node.Component of more
+   routes. Packages are import paths or paths relative to the package
+   directory like ./pages. They can't be main packages and must build for the
+   host, components that use syscall/js can't be prerendered.
+
+	greact_gen static --route /=./pages.Home --route /docs=./pages.Docs
+
+   The page for / is index.html, /docs is docs/index.html and routes ending
+   with .html like /404.html are written as they are. Routes can also be set
+   in ` + configName + ` with "routes" and "routeTable".`,
+		Flags: append(buildFlags(),
+			cli.StringFlag{
+				Name:  "dist",
+				Usage: "directory the pages and bundle are written to, defaults to dist in the package directory",
+			},
+			cli.StringSliceFlag{
+				Name:  "route",
+				Usage: "page to render, as path=pkg.Type",
+			},
+			cli.StringFlag{
+				Name:  "routes",
+				Usage: "package level map[string]node.Component of pages to render, as pkg.Var",
+			},
+		),
+		Action: static,
+	}
+}
+
+func static(ctx *cli.Context) error {
+	a := ctx.Args().First()
+	if a == "" {
+		a = "."
+	}
+	a, err := filepath.Abs(a)
+	if err != nil {
+		return err
+	}
+	c, err := loadConfig(ctx, a)
+	if err != nil {
+		return err
+	}
+	for _, v := range ctx.StringSlice("route") {
+		i := strings.IndexByte(v, '=')
+		if i == -1 {
+			return fmt.Errorf("route %q: expected path=pkg.Type", v)
+		}
+		if c.Routes == nil {
+			c.Routes = make(map[string]string)
+		}
+		c.Routes[v[:i]] = v[i+1:]
+	}
+	if ctx.IsSet("routes") {
+		c.RouteTable = ctx.String("routes")
+	}
+	if len(c.Routes) == 0 && c.RouteTable == "" {
+		return fmt.Errorf("no routes to render, see greact_gen static --help")
+	}
+	dirs, err := gen.PackageDirs(filepath.Join(a, "..."))
+	if err != nil {
+		return err
+	}
+	if err := renderDirs(dirs, c.buildTags()); err != nil {
+		return err
+	}
+	pages, err := renderPages(a, c)
+	if err != nil {
+		return err
+	}
+	b, err := newBundle(a, c, pages)
+	if err != nil {
+		return err
+	}
+	return b.summary(os.Stdout)
+}
+
+// ref is a reference to a package level name, like a component type.
+type ref struct {
+	pkg, name string
+}
+
+// parseRef parses pkg.Name, relative packages are resolved from dir.
+func parseRef(dir, s string) (ref, error) {
+	i := strings.LastIndexByte(s, '.')
+	if i <= 0 || !isIdent(s[i+1:]) {
+		return ref{}, fmt.Errorf("%q: expected package.Name", s)
+	}
+	r := ref{pkg: s[:i], name: s[i+1:]}
+	if r.pkg == "." || strings.HasPrefix(r.pkg, "./") || strings.HasPrefix(r.pkg, "../") {
+		p, err := importPath(dir, r.pkg)
+		if err != nil {
+			return ref{}, fmt.Errorf("%q: %v", s, err)
+		}
+		r.pkg = p
+	}
+	return r, nil
+}
+
+func isIdent(s string) bool {
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// importPath returns the import path of the package at rel in dir, main
+// packages are rejected since they can't be imported.
+func importPath(dir, rel string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "list", "-f", "{{.ImportPath}} {{.Name}}", rel)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("go list: %v\n%s", err, stderr.String())
+	}
+	f := strings.Fields(string(out))
+	if len(f) != 2 {
+		return "", fmt.Errorf("go list: unexpected output %q", out)
+	}
+	if f[1] == "main" {
+		return "", fmt.Errorf("%s is a main package, components to prerender must be in other packages", f[0])
+	}
+	return f[0], nil
+}
+
+// hostSource is the program that renders the pages and prints them as json.
+var hostSource = template.Must(template.New("host").Parse(`// Code generated by greact_gen static. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gernest/greact/node"
+	"github.com/gernest/greact/ssr"
+{{range $path, $name := .Imports}}	{{$name}} "{{$path}}"
+{{end}})
+
+func main() {
+	routes := make(map[string]node.Component)
+{{with .Table}}	for k, v := range {{.}} {
+		routes[k] = v
+	}
+{{end}}{{range .Routes}}	routes[{{printf "%q" .Path}}] = &{{.Type}}{}
+{{end}}	if err := ssr.WritePages(os.Stdout, routes); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+type hostRoute struct {
+	Path, Type string
+}
+
+// hostProgram returns the source of the program that renders routes and the
+// route table of c, package paths are resolved from dir.
+func hostProgram(dir string, c *config) ([]byte, error) {
+	imports := make(map[string]string)
+	qualify := func(s string) (string, error) {
+		r, err := parseRef(dir, s)
+		if err != nil {
+			return "", err
+		}
+		name, ok := imports[r.pkg]
+		if !ok {
+			name = fmt.Sprintf("p%d", len(imports))
+			imports[r.pkg] = name
+		}
+		return name + "." + r.name, nil
+	}
+	var data struct {
+		Imports map[string]string
+		Table   string
+		Routes  []hostRoute
+	}
+	if c.RouteTable != "" {
+		t, err := qualify(c.RouteTable)
+		if err != nil {
+			return nil, err
+		}
+		data.Table = t
+	}
+	paths := make([]string, 0, len(c.Routes))
+	for p := range c.Routes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if !strings.HasPrefix(p, "/") {
+			return nil, fmt.Errorf("route %s: path must start with /", p)
+		}
+		typ, err := qualify(c.Routes[p])
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %v", p, err)
+		}
+		data.Routes = append(data.Routes, hostRoute{Path: p, Type: typ})
+	}
+	data.Imports = imports
+	var buf bytes.Buffer
+	if err := hostSource.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// renderPages runs the host program for c in dir and returns the pages it
+// rendered.
+func renderPages(dir string, c *config) (map[string]string, error) {
+	src, err := hostProgram(dir, c)
+	if err != nil {
+		return nil, err
+	}
+	host := filepath.Join(dir, hostDir)
+	if err := os.MkdirAll(host, 0755); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(host)
+	if err := ioutil.WriteFile(filepath.Join(host, "main.go"), src, 0644); err != nil {
+		return nil, err
+	}
+	args := []string{"run"}
+	if c.Tags != "" {
+		args = append(args, "-tags", c.Tags)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", append(args, "./"+hostDir)...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("rendering pages: %v\n%s", err, stderr.String())
+	}
+	pages := make(map[string]string)
+	if err := json.Unmarshal(out, &pages); err != nil {
+		return nil, fmt.Errorf("rendering pages: %v", err)
+	}
+	return pages, nil
+}
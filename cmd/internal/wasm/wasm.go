@@ -0,0 +1,229 @@
+// Package wasm reads the sections of WebAssembly modules and the sizes and
+// names of their functions, which is enough to tell what makes a module big.
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Standard section ids.
+const (
+	customSection = 0
+	importSection = 2
+	codeSection   = 10
+)
+
+var sectionNames = []string{
+	"custom", "type", "import", "function", "table", "memory", "global",
+	"export", "start", "element", "code", "data", "datacount",
+}
+
+// Section is a section of a module.
+type Section struct {
+	ID byte
+
+	// Name is the name of the section, custom sections have their own names.
+	Name string
+
+	// Size is the size of the section content in bytes.
+	Size int
+}
+
+// Func is a function defined by a module.
+type Func struct {
+	// Index is the index of the function, imported functions come first.
+	Index int
+
+	// Name is the name of the function from the name section, it is empty
+	// when the module has no names.
+	Name string
+
+	// Size is the size of the function body in bytes.
+	Size int
+}
+
+// Module holds the sections and functions of a module.
+type Module struct {
+	Sections []Section
+	Funcs    []Func
+}
+
+// Parse reads the module in b.
+func Parse(b []byte) (*Module, error) {
+	magic := []byte{0, 'a', 's', 'm', 1, 0, 0, 0}
+	if !bytes.HasPrefix(b, magic) {
+		return nil, errors.New("wasm: not a version 1 module")
+	}
+	r := &reader{b: b, off: len(magic)}
+	m := &Module{}
+	imported := 0
+	var names map[int]string
+	for !r.done() {
+		id := r.byte()
+		size := r.uint()
+		if r.err != nil {
+			return nil, r.err
+		}
+		start := r.off
+		if size > len(b)-start {
+			return nil, fmt.Errorf("wasm: section %d at %d is truncated", id, start)
+		}
+		s := &reader{b: b[:start+size], off: start}
+		sec := Section{ID: id, Size: size}
+		switch {
+		case id == customSection:
+			sec.Name = s.name()
+			if sec.Name == "name" {
+				names = s.funcNames()
+			}
+		case int(id) < len(sectionNames):
+			sec.Name = sectionNames[id]
+		default:
+			sec.Name = fmt.Sprintf("unknown(%d)", id)
+		}
+		switch id {
+		case importSection:
+			imported = s.funcImports()
+		case codeSection:
+			n := s.uint()
+			for i := 0; i < n && s.err == nil; i++ {
+				size := s.uint()
+				m.Funcs = append(m.Funcs, Func{Index: imported + i, Size: size})
+				s.skip(size)
+			}
+		}
+		if s.err != nil {
+			return nil, fmt.Errorf("wasm: %s section: %v", sec.Name, s.err)
+		}
+		m.Sections = append(m.Sections, sec)
+		r.off = start + size
+	}
+	for i := range m.Funcs {
+		m.Funcs[i].Name = names[m.Funcs[i].Index]
+	}
+	return m, nil
+}
+
+// reader decodes values of the binary format, the first error sticks and
+// makes all reads return zero values.
+type reader struct {
+	b   []byte
+	off int
+	err error
+}
+
+func (r *reader) done() bool {
+	return r.err != nil || r.off >= len(r.b)
+}
+
+func (r *reader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *reader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if r.off >= len(r.b) {
+		r.fail(fmt.Errorf("unexpected end at %d", r.off))
+		return 0
+	}
+	c := r.b[r.off]
+	r.off++
+	return c
+}
+
+// uint reads an unsigned LEB128 number of at most 32 bits.
+func (r *reader) uint() int {
+	var v uint32
+	for shift := uint(0); shift < 35; shift += 7 {
+		c := r.byte()
+		v |= uint32(c&0x7f) << shift
+		if c&0x80 == 0 {
+			return int(v)
+		}
+	}
+	r.fail(fmt.Errorf("integer too long at %d", r.off))
+	return 0
+}
+
+func (r *reader) skip(n int) {
+	if r.err != nil {
+		return
+	}
+	if n > len(r.b)-r.off {
+		r.fail(fmt.Errorf("unexpected end at %d", r.off))
+		return
+	}
+	r.off += n
+}
+
+func (r *reader) name() string {
+	n := r.uint()
+	start := r.off
+	r.skip(n)
+	if r.err != nil {
+		return ""
+	}
+	return string(r.b[start:r.off])
+}
+
+// funcImports returns the number of imported functions.
+func (r *reader) funcImports() int {
+	n := r.uint()
+	funcs := 0
+	for i := 0; i < n && r.err == nil; i++ {
+		r.name()
+		r.name()
+		switch kind := r.byte(); kind {
+		case 0: // func
+			r.uint()
+			funcs++
+		case 1: // table
+			r.byte()
+			r.limits()
+		case 2: // memory
+			r.limits()
+		case 3: // global
+			r.byte()
+			r.byte()
+		default:
+			r.fail(fmt.Errorf("unknown import kind %d", kind))
+		}
+	}
+	return funcs
+}
+
+func (r *reader) limits() {
+	if r.byte() == 1 {
+		r.uint()
+	}
+	r.uint()
+}
+
+// funcNames reads the function names subsection of the name section.
+func (r *reader) funcNames() map[int]string {
+	names := make(map[int]string)
+	for !r.done() {
+		id := r.byte()
+		size := r.uint()
+		end := r.off + size
+		if id != 1 {
+			r.skip(size)
+			continue
+		}
+		n := r.uint()
+		for i := 0; i < n && r.err == nil; i++ {
+			idx := r.uint()
+			names[idx] = r.name()
+		}
+		if r.err == nil {
+			r.off = end
+		}
+	}
+	return names
+}
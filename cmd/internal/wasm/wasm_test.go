@@ -0,0 +1,78 @@
+package wasm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func section(id byte, content ...byte) []byte {
+	return append([]byte{id, byte(len(content))}, content...)
+}
+
+func name(s string) []byte {
+	return append([]byte{byte(len(s))}, s...)
+}
+
+func concat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
+
+func TestParse(t *testing.T) {
+	module := concat(
+		[]byte{0, 'a', 's', 'm', 1, 0, 0, 0},
+		section(1, 1, 0x60, 0, 0),
+		// a function and a memory import, only the function counts.
+		section(2, concat(
+			[]byte{2},
+			name("go"), name("debug"), []byte{0, 0},
+			name("go"), name("mem"), []byte{2, 0, 1},
+		)...),
+		section(3, 2, 0, 0),
+		// bodies of 2 and 4 bytes.
+		section(10, 2, 2, 0, 0x0b, 4, 0, 1, 1, 0x0b),
+		section(0, concat(
+			name("name"),
+			// a module name subsection that is skipped.
+			[]byte{0, 4}, name("app"),
+			[]byte{1, 16, 2, 1}, name("main.a"), []byte{2}, name("fmt.b"),
+		)...),
+	)
+	m, err := Parse(module)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectSections := []Section{
+		{1, "type", 4},
+		{2, "import", 22},
+		{3, "function", 3},
+		{10, "code", 9},
+		{0, "name", 29},
+	}
+	if !reflect.DeepEqual(m.Sections, expectSections) {
+		t.Errorf("expected sections %v got %v", expectSections, m.Sections)
+	}
+	expectFuncs := []Func{
+		{Index: 1, Name: "main.a", Size: 2},
+		{Index: 2, Name: "fmt.b", Size: 4},
+	}
+	if !reflect.DeepEqual(m.Funcs, expectFuncs) {
+		t.Errorf("expected funcs %v got %v", expectFuncs, m.Funcs)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	sample := map[string][]byte{
+		"not a module": []byte("\x00elf"),
+		"truncated":    {0, 'a', 's', 'm', 1, 0, 0, 0, 10, 20, 1},
+		"bad code":     {0, 'a', 's', 'm', 1, 0, 0, 0, 10, 2, 1, 9},
+	}
+	for desc, b := range sample {
+		if _, err := Parse(b); err == nil {
+			t.Errorf("%s: expected an error", desc)
+		}
+	}
+}
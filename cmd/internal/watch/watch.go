@@ -0,0 +1,110 @@
+// Package watch provides a polling file watcher. Polling is used instead of os
+// specific notification apis to keep things portable, the directories watched
+// by greact tools are small so the cost is negligible.
+package watch
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultInterval is the polling interval used when Watcher.Interval is zero.
+const DefaultInterval = 500 * time.Millisecond
+
+type fileState struct {
+	mod  time.Time
+	size int64
+}
+
+// Watcher watches files in a list of directories. Sub directories are not
+// watched.
+type Watcher struct {
+	// Dirs is the list of directories to watch.
+	Dirs []string
+
+	// Match reports whether the file at path is watched. All files are watched
+	// when Match is nil.
+	Match func(path string) bool
+
+	// Interval is how often the directories are scanned for changes.
+	Interval time.Duration
+
+	state map[string]fileState
+}
+
+// New returns a Watcher for dirs which watches files accepted by match.
+func New(match func(path string) bool, dirs ...string) *Watcher {
+	return &Watcher{Dirs: dirs, Match: match}
+}
+
+func (w *Watcher) scan() map[string]fileState {
+	s := make(map[string]fileState)
+	for _, dir := range w.Dirs {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			// The directory might be in the middle of being replaced, it will be
+			// picked up on the next scan.
+			continue
+		}
+		for _, f := range files {
+			if f.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, f.Name())
+			if w.Match != nil && !w.Match(path) {
+				continue
+			}
+			s[path] = fileState{mod: f.ModTime(), size: f.Size()}
+		}
+	}
+	return s
+}
+
+// Changed returns a sorted list of files that were created, modified or
+// removed since the last call. The first call only records the current state
+// and returns nil.
+func (w *Watcher) Changed() []string {
+	next := w.scan()
+	prev := w.state
+	w.state = next
+	if prev == nil {
+		return nil
+	}
+	var changed []string
+	for k, v := range next {
+		if p, ok := prev[k]; !ok || p != v {
+			changed = append(changed, k)
+		}
+	}
+	for k := range prev {
+		if _, ok := next[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// Run calls fn with the list of changed files every time a change is detected,
+// it blocks until stop is closed.
+func (w *Watcher) Run(stop <-chan struct{}, fn func(changed []string)) {
+	interval := w.Interval
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+	w.Changed()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			if c := w.Changed(); len(c) > 0 {
+				fn(c)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package watch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChanged(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	a := write("a.go", "package a")
+	write("a.txt", "ignored")
+	w := New(func(path string) bool {
+		return strings.HasSuffix(path, ".go")
+	}, dir)
+	if c := w.Changed(); c != nil {
+		t.Fatalf("expected first call to return nil got %v", c)
+	}
+	if c := w.Changed(); c != nil {
+		t.Errorf("expected no changes got %v", c)
+	}
+	write("a.go", "package a\n")
+	b := write("b.go", "package a")
+	write("a.txt", "still ignored")
+	if c := w.Changed(); !reflect.DeepEqual(c, []string{a, b}) {
+		t.Errorf("expected %v got %v", []string{a, b}, c)
+	}
+	os.Remove(b)
+	if c := w.Changed(); !reflect.DeepEqual(c, []string{b}) {
+		t.Errorf("expected %v got %v", []string{b}, c)
+	}
+}
@@ -0,0 +1,105 @@
+package dom
+
+import "github.com/gernest/greact/node"
+
+// Binding keeps the last value a generated update function set on a DOM node,
+// so nodes are only touched when the values bound to them change.
+type Binding struct {
+	init  bool
+	ok    bool
+	value string
+	class node.ClassList
+	style node.Style
+
+	handler  interface{}
+	listener *Func
+}
+
+// Text sets the nodeValue of text node n to s.
+func (b *Binding) Text(n Value, s string) {
+	if b.init && b.value == s {
+		return
+	}
+	b.init = true
+	b.value = s
+	n.Set("nodeValue", s)
+}
+
+// Attr sets attribute key of element el to v, see node.AttrString for how v is
+// converted to a string. Changes to class and style are applied with classList
+// and style.setProperty.
+func (b *Binding) Attr(el Value, key string, v interface{}) {
+	switch key {
+	case "class":
+		c := node.Classes(v)
+		if !b.init {
+			el.Call("setAttribute", key, c.String())
+		} else {
+			PatchClass(el, b.class, c)
+		}
+		b.class = c
+	case "style":
+		s := node.Styles(v)
+		if !b.init {
+			el.Call("setAttribute", key, s.String())
+		} else {
+			PatchStyle(el, b.style, s)
+		}
+		b.style = s
+	default:
+		s, ok := node.AttrString(key, v)
+		if b.init && b.ok == ok && b.value == s {
+			return
+		}
+		if ok {
+			el.Call("setAttribute", key, s)
+		} else if b.init && b.ok {
+			el.Call("removeAttribute", key)
+		}
+		b.ok = ok
+		b.value = s
+	}
+	b.init = true
+}
+
+// Toggle adds class name to element el when on is true and removes it
+// otherwise.
+func (b *Binding) Toggle(el Value, name string, on bool) {
+	if b.init && b.ok == on {
+		return
+	}
+	if on || b.init {
+		el.Get("classList").Call("toggle", name, on)
+	}
+	b.init = true
+	b.ok = on
+}
+
+// Listen makes handler the listener of event on element el. The DOM listener
+// is only added once, later calls replace the handler it dispatches to.
+//
+// handler can be func(), func(Value) which receives the event or
+// func(this Value, args []Value) interface{}.
+func (b *Binding) Listen(el Value, event string, handler interface{}) {
+	b.handler = handler
+	if b.listener != nil {
+		return
+	}
+	fn := FuncOf(func(this Value, args []Value) interface{} {
+		switch h := b.handler.(type) {
+		case func():
+			h()
+		case func(Value):
+			var e Value
+			if len(args) > 0 {
+				e = args[0]
+			}
+			h(e)
+		case func(Value, []Value) interface{}:
+			return h(this, args)
+		}
+		return nil
+	})
+	b.listener = &fn
+	el.Call("addEventListener", event, fn)
+}
@@ -126,7 +126,7 @@ func (v Value) Call(m string, args ...interface{}) Value {
 	for _, arg := range args {
 		a = append(a, ValueOf(arg))
 	}
-	return ValueOf(v.v.(Func).fn(v, a))
+	return ValueOf(prop.v.(Func).fn(v, a))
 }
 
 func IsNumber(v Value) bool {
@@ -5,3 +5,9 @@ import (
 )
 
 type Value = js.Value
+
+type Func = js.Func
+
+func FuncOf(fn func(this Value, args []Value) interface{}) Func {
+	return js.FuncOf(fn)
+}
@@ -0,0 +1,52 @@
+package dom
+
+import (
+	"sort"
+
+	"github.com/gernest/greact/node"
+)
+
+// PatchClass updates the classList of element el from old to n, only the
+// names that changed are added or removed.
+func PatchClass(el Value, old, n node.ClassList) {
+	add, remove := old.Diff(n)
+	if len(remove) == 0 && len(add) == 0 {
+		return
+	}
+	list := el.Get("classList")
+	if len(remove) > 0 {
+		list.Call("remove", values(remove)...)
+	}
+	if len(add) > 0 {
+		list.Call("add", values(add)...)
+	}
+}
+
+// PatchStyle updates inline styles of element el from old to n with
+// style.setProperty and style.removeProperty.
+func PatchStyle(el Value, old, n node.Style) {
+	set, remove := old.Diff(n)
+	if len(remove) == 0 && len(set) == 0 {
+		return
+	}
+	style := el.Get("style")
+	for _, k := range remove {
+		style.Call("removeProperty", k)
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		style.Call("setProperty", k, set[k])
+	}
+}
+
+func values(s []string) []interface{} {
+	a := make([]interface{}, len(s))
+	for i, v := range s {
+		a[i] = v
+	}
+	return a
+}
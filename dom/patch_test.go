@@ -0,0 +1,89 @@
+// +build !js
+
+package dom
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gernest/greact/node"
+)
+
+func recorder(calls *[]string) Value {
+	rec := func(name string) Func {
+		return FuncOf(func(this Value, args []Value) interface{} {
+			call := name
+			for _, a := range args {
+				call += " " + a.String()
+			}
+			*calls = append(*calls, call)
+			return nil
+		})
+	}
+	return ValueOf(map[string]interface{}{
+		"setAttribute":     rec("setAttribute"),
+		"removeAttribute":  rec("removeAttribute"),
+		"addEventListener": rec("addEventListener"),
+		"classList": map[string]interface{}{
+			"add":    rec("add"),
+			"remove": rec("remove"),
+			"toggle": rec("toggle"),
+		},
+		"style": map[string]interface{}{
+			"setProperty":    rec("setProperty"),
+			"removeProperty": rec("removeProperty"),
+		},
+	})
+}
+
+func TestPatchClass(t *testing.T) {
+	var calls []string
+	el := recorder(&calls)
+	PatchClass(el, node.Classes("a b"), node.Classes("b c d"))
+	PatchClass(el, node.Classes("a"), node.Classes("a"))
+	expect := []string{"remove a", "add c d"}
+	if !reflect.DeepEqual(calls, expect) {
+		t.Errorf("expected %v got %v", expect, calls)
+	}
+}
+
+func TestPatchStyle(t *testing.T) {
+	var calls []string
+	el := recorder(&calls)
+	PatchStyle(el, node.Styles("color: red; margin: 0"), node.Styles("color: red; width: 1px"))
+	expect := []string{"removeProperty margin", "setProperty width 1px"}
+	if !reflect.DeepEqual(calls, expect) {
+		t.Errorf("expected %v got %v", expect, calls)
+	}
+}
+
+func TestBinding(t *testing.T) {
+	var calls []string
+	el := recorder(&calls)
+	var attr, class, toggle, text, listen Binding
+	for i := 0; i < 2; i++ {
+		attr.Attr(el, "title", "hello")
+		class.Attr(el, "class", []string{"a"})
+		toggle.Toggle(el, "on", false)
+		text.Text(el, "text")
+		listen.Listen(el, "click", func() {})
+	}
+	attr.Attr(el, "title", nil)
+	class.Attr(el, "class", map[string]bool{"b": true})
+	toggle.Toggle(el, "on", true)
+	expect := []string{
+		"setAttribute title hello",
+		"setAttribute class a",
+		"addEventListener click <function>",
+		"removeAttribute title",
+		"remove a",
+		"add b",
+		"toggle on <boolean: true>",
+	}
+	if !reflect.DeepEqual(calls, expect) {
+		t.Errorf("expected %v got %v", expect, calls)
+	}
+	if v := el.Get("nodeValue").String(); v != "text" {
+		t.Errorf("expected text got %s", v)
+	}
+}
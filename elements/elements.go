@@ -1,152 +1,153 @@
+// Code generated by greact_gen elems from html spec 2026-10-19. DO NOT EDIT.
+
 package elements
 
 var elems = map[string]struct{}{
-	"a":                      struct{}{},
-	"abbr":                   struct{}{},
-	"acronym":                struct{}{},
-	"address":                struct{}{},
-	"applet":                 struct{}{},
-	"area":                   struct{}{},
-	"article":                struct{}{},
-	"aside":                  struct{}{},
-	"audio":                  struct{}{},
-	"b":                      struct{}{},
-	"base":                   struct{}{},
-	"basefont":               struct{}{},
-	"bdi":                    struct{}{},
-	"bdo":                    struct{}{},
-	"bgsound":                struct{}{},
-	"big":                    struct{}{},
-	"blink":                  struct{}{},
-	"blockquote":             struct{}{},
-	"body":                   struct{}{},
-	"br":                     struct{}{},
-	"button":                 struct{}{},
-	"canvas":                 struct{}{},
-	"caption":                struct{}{},
-	"center":                 struct{}{},
-	"cite":                   struct{}{},
-	"code":                   struct{}{},
-	"col":                    struct{}{},
-	"colgroup":               struct{}{},
-	"command":                struct{}{},
-	"content":                struct{}{},
-	"data":                   struct{}{},
-	"datalist":               struct{}{},
-	"dd":                     struct{}{},
-	"del":                    struct{}{},
-	"details":                struct{}{},
-	"dfn":                    struct{}{},
-	"dialog":                 struct{}{},
-	"dir":                    struct{}{},
-	"div":                    struct{}{},
-	"dl":                     struct{}{},
-	"dt":                     struct{}{},
-	"element":                struct{}{},
-	"em":                     struct{}{},
-	"embed":                  struct{}{},
-	"fieldset":               struct{}{},
-	"figcaption":             struct{}{},
-	"figure":                 struct{}{},
-	"font":                   struct{}{},
-	"footer":                 struct{}{},
-	"form":                   struct{}{},
-	"frame":                  struct{}{},
-	"frameset":               struct{}{},
-	"h1":                     struct{}{},
-	"h1, h2, h3, h4, h5, h6": struct{}{},
-	"h2":                     struct{}{},
-	"h3":                     struct{}{},
-	"h4":                     struct{}{},
-	"h5":                     struct{}{},
-	"h6":                     struct{}{},
-	"head":                   struct{}{},
-	"header":                 struct{}{},
-	"hgroup":                 struct{}{},
-	"hr":                     struct{}{},
-	"html":                   struct{}{},
-	"i":                      struct{}{},
-	"iframe":                 struct{}{},
-	"image":                  struct{}{},
-	"img":                    struct{}{},
-	"input":                  struct{}{},
-	"ins":                    struct{}{},
-	"isindex":                struct{}{},
-	"kbd":                    struct{}{},
-	"keygen":                 struct{}{},
-	"label":                  struct{}{},
-	"legend":                 struct{}{},
-	"li":                     struct{}{},
-	"link":                   struct{}{},
-	"listing":                struct{}{},
-	"main":                   struct{}{},
-	"map":                    struct{}{},
-	"mark":                   struct{}{},
-	"marquee":                struct{}{},
-	"menu":                   struct{}{},
-	"menuitem":               struct{}{},
-	"meta":                   struct{}{},
-	"meter":                  struct{}{},
-	"multicol":               struct{}{},
-	"nav":                    struct{}{},
-	"nextid":                 struct{}{},
-	"nobr":                   struct{}{},
-	"noembed":                struct{}{},
-	"noframes":               struct{}{},
-	"noscript":               struct{}{},
-	"object":                 struct{}{},
-	"ol":                     struct{}{},
-	"optgroup":               struct{}{},
-	"option":                 struct{}{},
-	"output":                 struct{}{},
-	"p":                      struct{}{},
-	"param":                  struct{}{},
-	"picture":                struct{}{},
-	"plaintext":              struct{}{},
-	"pre":                    struct{}{},
-	"progress":               struct{}{},
-	"q":                      struct{}{},
-	"rb":                     struct{}{},
-	"rp":                     struct{}{},
-	"rt":                     struct{}{},
-	"rtc":                    struct{}{},
-	"ruby":                   struct{}{},
-	"s":                      struct{}{},
-	"samp":                   struct{}{},
-	"script":                 struct{}{},
-	"section":                struct{}{},
-	"select":                 struct{}{},
-	"shadow":                 struct{}{},
-	"slot":                   struct{}{},
-	"small":                  struct{}{},
-	"source":                 struct{}{},
-	"spacer":                 struct{}{},
-	"span":                   struct{}{},
-	"strike":                 struct{}{},
-	"strong":                 struct{}{},
-	"style":                  struct{}{},
-	"sub":                    struct{}{},
-	"summary":                struct{}{},
-	"sup":                    struct{}{},
-	"table":                  struct{}{},
-	"tbody":                  struct{}{},
-	"td":                     struct{}{},
-	"template":               struct{}{},
-	"textarea":               struct{}{},
-	"tfoot":                  struct{}{},
-	"th":                     struct{}{},
-	"thead":                  struct{}{},
-	"time":                   struct{}{},
-	"title":                  struct{}{},
-	"tr":                     struct{}{},
-	"track":                  struct{}{},
-	"tt":                     struct{}{},
-	"u":                      struct{}{},
-	"ul":                     struct{}{},
-	"var":                    struct{}{},
-	"video":                  struct{}{},
-	"wbr":                    struct{}{},
-	"xmp":                    struct{}{},
+	"a":          struct{}{},
+	"abbr":       struct{}{},
+	"acronym":    struct{}{},
+	"address":    struct{}{},
+	"applet":     struct{}{},
+	"area":       struct{}{},
+	"article":    struct{}{},
+	"aside":      struct{}{},
+	"audio":      struct{}{},
+	"b":          struct{}{},
+	"base":       struct{}{},
+	"basefont":   struct{}{},
+	"bdi":        struct{}{},
+	"bdo":        struct{}{},
+	"bgsound":    struct{}{},
+	"big":        struct{}{},
+	"blink":      struct{}{},
+	"blockquote": struct{}{},
+	"body":       struct{}{},
+	"br":         struct{}{},
+	"button":     struct{}{},
+	"canvas":     struct{}{},
+	"caption":    struct{}{},
+	"center":     struct{}{},
+	"cite":       struct{}{},
+	"code":       struct{}{},
+	"col":        struct{}{},
+	"colgroup":   struct{}{},
+	"command":    struct{}{},
+	"content":    struct{}{},
+	"data":       struct{}{},
+	"datalist":   struct{}{},
+	"dd":         struct{}{},
+	"del":        struct{}{},
+	"details":    struct{}{},
+	"dfn":        struct{}{},
+	"dialog":     struct{}{},
+	"dir":        struct{}{},
+	"div":        struct{}{},
+	"dl":         struct{}{},
+	"dt":         struct{}{},
+	"element":    struct{}{},
+	"em":         struct{}{},
+	"embed":      struct{}{},
+	"fieldset":   struct{}{},
+	"figcaption": struct{}{},
+	"figure":     struct{}{},
+	"font":       struct{}{},
+	"footer":     struct{}{},
+	"form":       struct{}{},
+	"frame":      struct{}{},
+	"frameset":   struct{}{},
+	"h1":         struct{}{},
+	"h2":         struct{}{},
+	"h3":         struct{}{},
+	"h4":         struct{}{},
+	"h5":         struct{}{},
+	"h6":         struct{}{},
+	"head":       struct{}{},
+	"header":     struct{}{},
+	"hgroup":     struct{}{},
+	"hr":         struct{}{},
+	"html":       struct{}{},
+	"i":          struct{}{},
+	"iframe":     struct{}{},
+	"image":      struct{}{},
+	"img":        struct{}{},
+	"input":      struct{}{},
+	"ins":        struct{}{},
+	"isindex":    struct{}{},
+	"kbd":        struct{}{},
+	"keygen":     struct{}{},
+	"label":      struct{}{},
+	"legend":     struct{}{},
+	"li":         struct{}{},
+	"link":       struct{}{},
+	"listing":    struct{}{},
+	"main":       struct{}{},
+	"map":        struct{}{},
+	"mark":       struct{}{},
+	"marquee":    struct{}{},
+	"menu":       struct{}{},
+	"menuitem":   struct{}{},
+	"meta":       struct{}{},
+	"meter":      struct{}{},
+	"multicol":   struct{}{},
+	"nav":        struct{}{},
+	"nextid":     struct{}{},
+	"nobr":       struct{}{},
+	"noembed":    struct{}{},
+	"noframes":   struct{}{},
+	"noscript":   struct{}{},
+	"object":     struct{}{},
+	"ol":         struct{}{},
+	"optgroup":   struct{}{},
+	"option":     struct{}{},
+	"output":     struct{}{},
+	"p":          struct{}{},
+	"param":      struct{}{},
+	"picture":    struct{}{},
+	"plaintext":  struct{}{},
+	"pre":        struct{}{},
+	"progress":   struct{}{},
+	"q":          struct{}{},
+	"rb":         struct{}{},
+	"rp":         struct{}{},
+	"rt":         struct{}{},
+	"rtc":        struct{}{},
+	"ruby":       struct{}{},
+	"s":          struct{}{},
+	"samp":       struct{}{},
+	"script":     struct{}{},
+	"section":    struct{}{},
+	"select":     struct{}{},
+	"shadow":     struct{}{},
+	"slot":       struct{}{},
+	"small":      struct{}{},
+	"source":     struct{}{},
+	"spacer":     struct{}{},
+	"span":       struct{}{},
+	"strike":     struct{}{},
+	"strong":     struct{}{},
+	"style":      struct{}{},
+	"sub":        struct{}{},
+	"summary":    struct{}{},
+	"sup":        struct{}{},
+	"table":      struct{}{},
+	"tbody":      struct{}{},
+	"td":         struct{}{},
+	"template":   struct{}{},
+	"textarea":   struct{}{},
+	"tfoot":      struct{}{},
+	"th":         struct{}{},
+	"thead":      struct{}{},
+	"time":       struct{}{},
+	"title":      struct{}{},
+	"tr":         struct{}{},
+	"track":      struct{}{},
+	"tt":         struct{}{},
+	"u":          struct{}{},
+	"ul":         struct{}{},
+	"var":        struct{}{},
+	"video":      struct{}{},
+	"wbr":        struct{}{},
+	"xmp":        struct{}{},
 }
 
 // Valid returns true if the name is a valid html element
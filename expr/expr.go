@@ -43,56 +43,165 @@ func (e Expression) quoteExpr() (ast.Expr, error) {
 // Leading and trailing space is trimmed. So, It is not possible to reconstruct
 // original text from the returned expressions.
 //
+// Expressions may contain nested begin,end markers as long as they are
+// balanced, this allows composite literals and function literals to be used
+// inline like {[]string{"a"}}. Markers inside string and rune literals are
+// ignored. To write a literal marker in plain text, double it, so {{ yields {
+// and }} yields }.
+//
 // Note that the expression must be valid go expressions.
 func ExtractExpressions(src string, begin, end rune) (result []Expression, err error) {
+	s := &scanner{src: []rune(src), line: 1, col: 1}
 	var buf bytes.Buffer
-	line, col, count := 0, 0, 0
-	for _, v := range src {
+	flush := func() {
+		txt := strings.TrimSpace(buf.String())
+		if txt != "" {
+			result = append(result, Expression{
+				Text:  txt,
+				Plain: true,
+			})
+		}
+		buf.Reset()
+	}
+	for !s.eof() {
+		v := s.peek()
 		switch v {
 		case begin:
-			if buf.Len() > 0 {
-				txt := strings.TrimSpace(buf.String())
-				if txt != "" {
-					result = append(result, Expression{
-						Text:  txt,
-						Plain: true,
-					})
-				}
-				buf.Reset()
+			if s.peekN(1) == begin {
+				s.next()
+				s.next()
+				buf.WriteRune(begin)
+				continue
 			}
-			if count != 0 {
-				err = fmt.Errorf("unexpected %s at %d:%d", string(v), line, col)
+			flush()
+			line, col := s.line, s.col
+			s.next()
+			txt, ok := s.expression(begin, end)
+			if !ok {
+				err = fmt.Errorf("unterminated %s at %d:%d", string(begin), line, col)
 				return
 			}
-			count++
-			col++
-		case end:
 			result = append(result, Expression{
-				Text: buf.String(),
+				Text: txt,
 			})
-			buf.Reset()
-			count--
-			col++
+		case end:
+			if s.peekN(1) == end {
+				s.next()
+				s.next()
+				buf.WriteRune(end)
+				continue
+			}
+			err = fmt.Errorf("unexpected %s at %d:%d", string(v), s.line, s.col)
+			return
 		default:
-			switch v {
-			case '\n', '\r':
-				line++
-			default:
-				col++
+			buf.WriteRune(s.next())
+		}
+	}
+	flush()
+	return
+}
+
+// ScanExpression reads the expression at the start of src which must begin
+// with the begin marker. It returns the text between the balancing markers and
+// the number of bytes of src the expression spans including the markers.
+func ScanExpression(src string, begin, end rune) (text string, size int, err error) {
+	s := &scanner{src: []rune(src), line: 1, col: 1}
+	if s.eof() || s.next() != begin {
+		return "", 0, fmt.Errorf("expected %s at 1:1", string(begin))
+	}
+	text, ok := s.expression(begin, end)
+	if !ok {
+		return "", 0, fmt.Errorf("unterminated %s at 1:1", string(begin))
+	}
+	return text, len(string(s.src[:s.pos])), nil
+}
+
+// scanner walks the source text keeping track of the position for error
+// reporting.
+type scanner struct {
+	src       []rune
+	pos       int
+	line, col int
+}
+
+func (s *scanner) eof() bool {
+	return s.pos >= len(s.src)
+}
+
+func (s *scanner) peek() rune {
+	return s.peekN(0)
+}
+
+func (s *scanner) peekN(n int) rune {
+	if s.pos+n >= len(s.src) {
+		return 0
+	}
+	return s.src[s.pos+n]
+}
+
+func (s *scanner) next() rune {
+	v := s.src[s.pos]
+	s.pos++
+	if v == '\n' {
+		s.line++
+		s.col = 1
+	} else {
+		s.col++
+	}
+	return v
+}
+
+// expression reads text until the end marker that balances the begin marker
+// which has already been consumed. The returned text excludes the closing
+// marker. ok is false if the source ends before the expression is closed.
+func (s *scanner) expression(begin, end rune) (txt string, ok bool) {
+	var buf bytes.Buffer
+	depth := 1
+	for !s.eof() {
+		v := s.next()
+		switch v {
+		case begin:
+			depth++
+		case end:
+			depth--
+			if depth == 0 {
+				return buf.String(), true
+			}
+		case '"', '\'':
+			buf.WriteRune(v)
+			if !s.quoted(&buf, v, true) {
+				return "", false
 			}
+			continue
+		case '`':
 			buf.WriteRune(v)
+			if !s.quoted(&buf, v, false) {
+				return "", false
+			}
+			continue
 		}
+		buf.WriteRune(v)
 	}
-	if buf.Len() > 0 {
-		txt := strings.TrimSpace(buf.String())
-		if txt != "" {
-			result = append(result, Expression{
-				Text:  txt,
-				Plain: true,
-			})
+	return "", false
+}
+
+// quoted copies a string or rune literal whose opening quote has already been
+// consumed.
+func (s *scanner) quoted(buf *bytes.Buffer, quote rune, escapes bool) bool {
+	for !s.eof() {
+		v := s.next()
+		buf.WriteRune(v)
+		switch {
+		case v == quote:
+			return true
+		case v == '\\' && escapes:
+			if s.eof() {
+				return false
+			}
+			buf.WriteRune(s.next())
 		}
 	}
-	return
+	return false
 }
 
 // Parse returns ast.Expr wtih exp interpreted as function body of a
@@ -185,6 +294,9 @@ func Eval(args ...interface{}) string {
 			if g != nil {
 				buf.WriteString(toValue(g))
 			}
+		case nil:
+		default:
+			buf.WriteString(toValue(v))
 		}
 	}
 	return buf.String()
@@ -36,6 +36,36 @@ func TestExtractExpression(t *testing.T) {
 			{Text: "hello, world", Plain: true},
 			{Text: "a", Plain: false},
 		}},
+
+		// nested braces
+		{`{[]string{"a"}}`, []Expression{
+			{Text: `[]string{"a"}`, Plain: false},
+		}},
+		{"{func() int { return 1 }()}", []Expression{
+			{Text: "func() int { return 1 }()", Plain: false},
+		}},
+
+		// braces inside string and rune literals
+		{`{"}" + "{"}`, []Expression{
+			{Text: `"}" + "{"`, Plain: false},
+		}},
+		{"{'}'}{`{`}", []Expression{
+			{Text: "'}'", Plain: false},
+			{Text: "`{`", Plain: false},
+		}},
+		{`{"\"}"}`, []Expression{
+			{Text: `"\"}"`, Plain: false},
+		}},
+
+		// escaped braces
+		{"{{a}}", []Expression{
+			{Text: "{a}", Plain: true},
+		}},
+		{"a {{ {b} }}", []Expression{
+			{Text: "a {", Plain: true},
+			{Text: "b", Plain: false},
+			{Text: "}", Plain: true},
+		}},
 	}
 	for _, v := range sample {
 		e, err := ExtractExpressions(v.src, '{', '}')
@@ -47,3 +77,41 @@ func TestExtractExpression(t *testing.T) {
 		}
 	}
 }
+
+func TestExtractExpressionError(t *testing.T) {
+	sample := []struct {
+		src, err string
+	}{
+		{"{a", "unterminated { at 1:1"},
+		{"hello\n {a{b}", "unterminated { at 2:2"},
+		{"a}", "unexpected } at 1:2"},
+		{`{"}`, "unterminated { at 1:1"},
+	}
+	for _, v := range sample {
+		_, err := ExtractExpressions(v.src, '{', '}')
+		if err == nil {
+			t.Errorf("%q: expected an error", v.src)
+			continue
+		}
+		if err.Error() != v.err {
+			t.Errorf("%q: expected %s got %s", v.src, v.err, err)
+		}
+	}
+}
+
+func TestScanExpression(t *testing.T) {
+	text, size, err := ScanExpression(`{map[string]string{"a": "}"}} rest`, '{', '}')
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `map[string]string{"a": "}"}`
+	if text != expect {
+		t.Errorf("expected %s got %s", expect, text)
+	}
+	if size != len(expect)+2 {
+		t.Errorf("expected %d got %d", len(expect)+2, size)
+	}
+	if _, _, err := ScanExpression("{a", '{', '}'); err == nil {
+		t.Error("expected an error")
+	}
+}
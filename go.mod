@@ -4,6 +4,7 @@ go 1.12
 
 require (
 	github.com/PuerkitoBio/goquery v1.5.0
+	github.com/andybalholm/brotli v1.0.6
 	github.com/urfave/cli v1.20.0
 	golang.org/x/net v0.0.0-20181217023233-e147a9138326
 )
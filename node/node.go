@@ -2,6 +2,10 @@ package node
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/gernest/greact/expr"
 )
@@ -58,24 +62,100 @@ type Node struct {
 	Namespace string
 	Attr      []Attribute
 	Children  []*Node
+
+	// Static is true for nodes that are built once and shared by every
+	// render, see Static. They are read-only: renderers and reconcilers can
+	// skip diffing a static node that is rendered again, and code that
+	// changes rendered trees must replace static nodes with copies instead
+	// of modifying them.
+	Static bool
 }
 
 type Component interface {
 	Render(context.Context, Props, State) *Node
 }
 
+// SpreadKey is the key of attributes whose values are expanded to a list of
+// attributes by New. The value can be []Attribute, Props,
+// map[string]interface{} or map[string]string.
+const SpreadKey = "..."
+
+// ClassPrefix is the prefix of conditional class attributes. The attribute
+// class:active={cond} adds active to the class attribute when cond is true.
+const ClassPrefix = "class:"
+
 // New is a wrapper for creating new node. If children are provided adjacent
 // text nodes will be merged to a single node.
+//
+// Spread attributes are expanded in place, conditional class attributes are
+// merged to the class attribute and attributes with nil values are dropped.
+// Boolean attributes of elements with false values are dropped too, so
+// disabled={false} is not set while aria-hidden={false} is. Attribute values
+// of elements, like props passed on with title={props["title"]}, are replaced
+// with the values they hold.
+//
+// Values of class and style attributes of elements are normalized to ClassList
+// and Style, see Classes and Styles for the values they accept.
 func New(typ NodeType, ns, name string, attrs []Attribute, children ...*Node) *Node {
+	return newNode(typ, ns, name, attrs, children)
+}
+
+var componentType = reflect.TypeOf((*Component)(nil)).Elem()
+
+// NewComponent returns a node for the component c, name is the tag it is used
+// with. c is the component value, its Render method can have a pointer
+// receiver. Attributes are handled like for New, they are the props of the
+// component.
+//
+// This panics if c is not a component.
+func NewComponent(c interface{}, name string, attrs []Attribute, children ...*Node) *Node {
+	if !IsComponent(c) {
+		panic(fmt.Sprintf("node: %T of <%s> is not a component", c, name))
+	}
+	return newNode(c, "", name, attrs, children)
+}
+
+// IsComponent returns true if v, or a pointer to it, implements Component.
+func IsComponent(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	t := reflect.TypeOf(v)
+	if t.Implements(componentType) {
+		return true
+	}
+	return t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(componentType)
+}
+
+func newNode(typ interface{}, ns, name string, attrs []Attribute, children []*Node) *Node {
 	var norm []Attribute
 	var key string
-	for _, v := range attrs {
-		if v.Key == "key" {
+	var classes []string
+	element := typ == ElementNode
+	for _, v := range expand(attrs) {
+		if a, ok := v.Val.(Attribute); ok && element {
+			v.Val = a.Val
+		}
+		switch {
+		case v.Key == "key":
 			key = expr.Eval(v.Val)
-		} else {
+		case strings.HasPrefix(v.Key, ClassPrefix):
+			if b, ok := v.Val.(bool); ok && b {
+				classes = append(classes, strings.TrimPrefix(v.Key, ClassPrefix))
+			}
+		case v.Val == nil:
+		case element && v.Val == false && IsBooleanAttr(v.Key):
+		case element && v.Key == "class":
+			norm = append(norm, Attribute{Namespace: v.Namespace, Key: v.Key, Val: Classes(v.Val)})
+		case element && v.Key == "style":
+			norm = append(norm, Attribute{Namespace: v.Namespace, Key: v.Key, Val: Styles(v.Val)})
+		default:
 			norm = append(norm, v)
 		}
 	}
+	if len(classes) > 0 {
+		norm = addClasses(norm, classes)
+	}
 	if len(children) > 0 {
 		norm = append(norm, Attribute{
 			Key: "children",
@@ -92,6 +172,126 @@ func New(typ NodeType, ns, name string, attrs []Attribute, children ...*Node) *N
 	return n
 }
 
+// expand replaces spread attributes in attrs with the attributes they hold.
+// Keys of maps are sorted to keep the order of attributes stable.
+func expand(attrs []Attribute) []Attribute {
+	spread := false
+	for _, v := range attrs {
+		if v.Key == SpreadKey {
+			spread = true
+			break
+		}
+	}
+	if !spread {
+		return attrs
+	}
+	var rst []Attribute
+	for _, v := range attrs {
+		if v.Key != SpreadKey {
+			rst = append(rst, v)
+			continue
+		}
+		switch e := v.Val.(type) {
+		case []Attribute:
+			rst = append(rst, e...)
+		case Props:
+			for _, k := range sortedKeys(e) {
+				a := e[k]
+				if a.Key == "" {
+					a.Key = k
+				}
+				rst = append(rst, a)
+			}
+		case map[string]interface{}:
+			for _, k := range sortedKeys(e) {
+				rst = append(rst, Attribute{Key: k, Val: e[k]})
+			}
+		case map[string]string:
+			for _, k := range sortedKeys(e) {
+				rst = append(rst, Attribute{Key: k, Val: e[k]})
+			}
+		}
+	}
+	return rst
+}
+
+// sortedKeys returns the sorted keys of m, a map with string keys.
+func sortedKeys(m interface{}) []string {
+	v := reflect.ValueOf(m)
+	keys := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// booleanAttrs are html attributes whose presence alone is meaningful.
+var booleanAttrs = map[string]bool{
+	"allowfullscreen": true,
+	"async":           true,
+	"autofocus":       true,
+	"autoplay":        true,
+	"checked":         true,
+	"controls":        true,
+	"default":         true,
+	"defer":           true,
+	"disabled":        true,
+	"formnovalidate":  true,
+	"hidden":          true,
+	"ismap":           true,
+	"itemscope":       true,
+	"loop":            true,
+	"multiple":        true,
+	"muted":           true,
+	"nomodule":        true,
+	"novalidate":      true,
+	"open":            true,
+	"playsinline":     true,
+	"readonly":        true,
+	"required":        true,
+	"reversed":        true,
+	"selected":        true,
+}
+
+// IsBooleanAttr returns true if key is a boolean html attribute, one that is
+// set by its presence like disabled. Other attributes like draggable or
+// aria-hidden take "true" and "false" as values.
+func IsBooleanAttr(key string) bool {
+	return booleanAttrs[strings.ToLower(key)]
+}
+
+// addClasses appends classes to the class attribute in attrs, the attribute is
+// added when there is none.
+func addClasses(attrs []Attribute, classes []string) []Attribute {
+	for i, v := range attrs {
+		if v.Key == "class" {
+			attrs[i].Val = Classes(append(Classes(v.Val), classes...))
+			return attrs
+		}
+	}
+	return append(attrs, Attribute{
+		Key: "class",
+		Val: Classes(classes),
+	})
+}
+
+// Static marks n and its descendants as static and returns n. The generator
+// uses this for parts of templates without expressions, they are built once
+// and shared by all renders so they must not be modified. The root node a
+// Render method returns is never static, only nodes below it can be.
+func Static(n *Node) *Node {
+	n.Static = true
+	for _, v := range n.Attr {
+		if c, ok := v.Val.([]*Node); ok && v.Key == "children" {
+			for _, child := range c {
+				Static(child)
+			}
+		}
+	}
+	return n
+}
+
 // Attr returns Attribute from the arguments. This doesn't do much appart from
 // wrapping the arguments.
 func Attr(ns, key string, val interface{}) Attribute {
@@ -1 +1,191 @@
 package node
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type button struct{}
+
+func (b *button) Render(ctx context.Context, props Props, state State) *Node {
+	return New(ElementNode, "", "button", nil)
+}
+
+func TestNew(t *testing.T) {
+	t.Run("spread", func(t *testing.T) {
+		n := New(3, "", "input", Attrs(
+			Attr("", "type", "text"),
+			Attr("", SpreadKey, map[string]interface{}{"name": "q", "id": "search"}),
+			Attr("", SpreadKey, []Attribute{Attr("", "value", "go")}),
+			Attr("", SpreadKey, Props{"required": Attr("", "required", true)}),
+		))
+		expect := []Attribute{
+			Attr("", "type", "text"),
+			Attr("", "id", "search"),
+			Attr("", "name", "q"),
+			Attr("", "value", "go"),
+			Attr("", "required", true),
+		}
+		if !reflect.DeepEqual(n.Attr, expect) {
+			t.Errorf("expected %v got %v", expect, n.Attr)
+		}
+		if n.Type != ElementNode {
+			t.Errorf("expected %v got %v", ElementNode, n.Type)
+		}
+	})
+	t.Run("conditional", func(t *testing.T) {
+		n := New(3, "", "li", Attrs(
+			Attr("", "class", "item"),
+			Attr("", "class:active", true),
+			Attr("", "class:hidden", false),
+			Attr("", "class:selected", true),
+			Attr("", "title", nil),
+			Attr("", "disabled", false),
+			Attr("", "draggable", false),
+			Attr("", "aria-expanded", false),
+			Attr("", "key", 1),
+		))
+		expect := []Attribute{
+			Attr("", "class", ClassList{"item", "active", "selected"}),
+			Attr("", "draggable", false),
+			Attr("", "aria-expanded", false),
+		}
+		if !reflect.DeepEqual(n.Attr, expect) {
+			t.Errorf("expected %v got %v", expect, n.Attr)
+		}
+		if n.Key != "1" {
+			t.Errorf("expected key 1 got %s", n.Key)
+		}
+		n = New(3, "", "li", Attrs(Attr("", "class:active", true)))
+		expect = []Attribute{Attr("", "class", ClassList{"active"})}
+		if !reflect.DeepEqual(n.Attr, expect) {
+			t.Errorf("expected %v got %v", expect, n.Attr)
+		}
+	})
+	t.Run("props", func(t *testing.T) {
+		props := Props{"title": Attr("", "title", "hello"), "hidden": Attr("", "hidden", false)}
+		n := New(3, "", "p", Attrs(
+			Attr("", "title", props["title"]),
+			Attr("", "hidden", props["hidden"]),
+			Attr("", "lang", props["missing"]),
+		))
+		expect := []Attribute{Attr("", "title", "hello")}
+		if !reflect.DeepEqual(n.Attr, expect) {
+			t.Errorf("expected %v got %v", expect, n.Attr)
+		}
+	})
+	t.Run("component", func(t *testing.T) {
+		n := NewComponent(button{}, "button", Attrs(Attr("", "disabled", false)))
+		expect := []Attribute{Attr("", "disabled", false)}
+		if !reflect.DeepEqual(n.Attr, expect) {
+			t.Errorf("expected %v got %v", expect, n.Attr)
+		}
+		if n.Type != (button{}) {
+			t.Errorf("expected the component value as type got %#v", n.Type)
+		}
+	})
+}
+
+func TestNewComponentPanics(t *testing.T) {
+	for _, v := range []interface{}{nil, ElementNode, 3, struct{}{}, &struct{}{}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected %#v to panic", v)
+				}
+			}()
+			NewComponent(v, "x", nil)
+		}()
+	}
+	for _, v := range []interface{}{button{}, &button{}} {
+		if !IsComponent(v) {
+			t.Errorf("expected %#v to be a component", v)
+		}
+	}
+}
+
+func TestClasses(t *testing.T) {
+	sample := []struct {
+		v      interface{}
+		expect string
+	}{
+		{"a  b a", "a b"},
+		{[]string{"a", "", "b"}, "a b"},
+		{map[string]bool{"b": true, "a": true, "c": false}, "a b"},
+		{ClassList{"a"}, "a"},
+		{nil, ""},
+	}
+	for _, v := range sample {
+		if got := Classes(v.v).String(); got != v.expect {
+			t.Errorf("%v: expected %q got %q", v.v, v.expect, got)
+		}
+	}
+	add, remove := Classes("a b c").Diff(Classes("b d"))
+	if !reflect.DeepEqual(add, []string{"d"}) || !reflect.DeepEqual(remove, []string{"a", "c"}) {
+		t.Errorf("expected [d] [a c] got %v %v", add, remove)
+	}
+}
+
+func TestStyles(t *testing.T) {
+	type css struct {
+		BackgroundColor string
+		Margin          int `css:"margin"`
+		Width           string
+		Ignored         string `css:"-"`
+	}
+	sample := []struct {
+		v      interface{}
+		expect string
+	}{
+		{"color: red; margin:0;;", "color: red; margin: 0;"},
+		{map[string]string{"color": "red", "width": ""}, "color: red;"},
+		{map[string]interface{}{"z-index": 2, "color": nil}, "z-index: 2;"},
+		{css{BackgroundColor: "red", Margin: 4, Ignored: "x"}, "background-color: red; margin: 4;"},
+		{&css{Width: "10px"}, "width: 10px;"},
+		{(*css)(nil), ""},
+	}
+	for _, v := range sample {
+		if got := Styles(v.v).String(); got != v.expect {
+			t.Errorf("%v: expected %q got %q", v.v, v.expect, got)
+		}
+	}
+	set, remove := Styles("color: red; margin: 0").Diff(Styles("color: blue; width: 1px"))
+	expect := Style{"color": "blue", "width": "1px"}
+	if !reflect.DeepEqual(set, expect) || !reflect.DeepEqual(remove, []string{"margin"}) {
+		t.Errorf("expected %v [margin] got %v %v", expect, set, remove)
+	}
+}
+
+func TestStatic(t *testing.T) {
+	child := New(1, "", "hello", nil)
+	n := Static(New(3, "", "p", nil, child))
+	if !n.Static || !child.Static {
+		t.Error("expected the node and its children to be static")
+	}
+}
+
+func TestAttrString(t *testing.T) {
+	sample := []struct {
+		key    string
+		v      interface{}
+		expect string
+		ok     bool
+	}{
+		{"title", "hello", "hello", true},
+		{"title", nil, "", false},
+		{"disabled", false, "", false},
+		{"disabled", true, "", true},
+		{"draggable", false, "false", true},
+		{"aria-hidden", true, "true", true},
+		{"tabindex", 2, "2", true},
+		{"class", []string{"a", "b"}, "a b", true},
+		{"style", map[string]string{"color": "red"}, "color: red;", true},
+	}
+	for _, v := range sample {
+		s, ok := AttrString(v.key, v.v)
+		if s != v.expect || ok != v.ok {
+			t.Errorf("%s=%v: expected %q %v got %q %v", v.key, v.v, v.expect, v.ok, s, ok)
+		}
+	}
+}
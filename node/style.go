@@ -0,0 +1,244 @@
+package node
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gernest/greact/expr"
+)
+
+// ClassList is the normalized value of the class attribute. New converts
+// values of class attributes to ClassList.
+type ClassList []string
+
+// Classes returns v as a ClassList. v can be a string of space separated
+// names, []string, map[string]bool where only names mapped to true are used or
+// a ClassList. Duplicate and empty names are removed and the order of names is
+// kept, names from maps are sorted.
+func Classes(v interface{}) ClassList {
+	var names []string
+	switch e := v.(type) {
+	case nil:
+	case ClassList:
+		names = e
+	case string:
+		names = strings.Fields(e)
+	case []string:
+		names = e
+	case map[string]bool:
+		for k, ok := range e {
+			if ok {
+				names = append(names, k)
+			}
+		}
+		sort.Strings(names)
+	default:
+		names = strings.Fields(fmt.Sprint(v))
+	}
+	var c ClassList
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		c = append(c, name)
+	}
+	return c
+}
+
+func (c ClassList) String() string {
+	return strings.Join(c, " ")
+}
+
+// Diff returns the names that must be removed from c and added to c to get
+// to n.
+func (c ClassList) Diff(n ClassList) (add, remove []string) {
+	old := make(map[string]bool)
+	for _, name := range c {
+		old[name] = true
+	}
+	next := make(map[string]bool)
+	for _, name := range n {
+		next[name] = true
+		if !old[name] {
+			add = append(add, name)
+		}
+	}
+	for _, name := range c {
+		if !next[name] {
+			remove = append(remove, name)
+		}
+	}
+	return
+}
+
+// Style is the normalized value of the style attribute, it maps css
+// properties to their values. New converts values of style attributes to
+// Style.
+type Style map[string]string
+
+// Styles returns v as Style. v can be a string of css declarations like
+// "color: red; margin: 0", map[string]string, map[string]interface{} or a
+// struct. Fields of structs are mapped to properties by the css struct tag or
+// the kebab cased field name, fields with zero values are skipped. Properties
+// with empty or nil values are removed.
+func Styles(v interface{}) Style {
+	s := make(Style)
+	switch e := v.(type) {
+	case nil:
+	case Style:
+		for k, v := range e {
+			s.set(k, v)
+		}
+	case map[string]string:
+		for k, v := range e {
+			s.set(k, v)
+		}
+	case map[string]interface{}:
+		for k, v := range e {
+			if v != nil {
+				s.set(k, fmt.Sprint(v))
+			}
+		}
+	case string:
+		for _, decl := range strings.Split(e, ";") {
+			i := strings.Index(decl, ":")
+			if i == -1 {
+				continue
+			}
+			s.set(decl[:i], decl[i+1:])
+		}
+	default:
+		rv := reflect.ValueOf(v)
+		for rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return s
+			}
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			return s
+		}
+		typ := rv.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			name := f.Tag.Get("css")
+			switch name {
+			case "-":
+				continue
+			case "":
+				name = kebabCase(f.Name)
+			}
+			fv := rv.Field(i)
+			if isZero(fv) {
+				continue
+			}
+			s.set(name, fmt.Sprint(fv.Interface()))
+		}
+	}
+	return s
+}
+
+func (s Style) set(k, v string) {
+	k = strings.TrimSpace(k)
+	v = strings.TrimSpace(v)
+	if k == "" || v == "" {
+		return
+	}
+	s[k] = v
+}
+
+// String returns css declarations of s sorted by property.
+func (s Style) String() string {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(k)
+		b.WriteString(": ")
+		b.WriteString(s[k])
+		b.WriteString(";")
+	}
+	return b.String()
+}
+
+// Diff returns properties that must be set on s and removed from s to get to
+// n. The properties to remove are sorted.
+func (s Style) Diff(n Style) (set Style, remove []string) {
+	set = make(Style)
+	for k, v := range n {
+		if s[k] != v {
+			set[k] = v
+		}
+	}
+	for k := range s {
+		if _, ok := n[k]; !ok {
+			remove = append(remove, k)
+		}
+	}
+	sort.Strings(remove)
+	return
+}
+
+// AttrString returns the string value of attribute key set to v, ok is false
+// if the attribute must not be set. Attributes with nil values are not set.
+// Boolean attributes, see IsBooleanAttr, are not set when v is false and set
+// to an empty string when it is true, other attributes are set to "true" or
+// "false". Values of class and style are normalized with Classes and Styles.
+func AttrString(key string, v interface{}) (s string, ok bool) {
+	switch e := v.(type) {
+	case nil:
+		return "", false
+	case bool:
+		if IsBooleanAttr(key) {
+			return "", e
+		}
+		return strconv.FormatBool(e), true
+	case string:
+		if key != "class" && key != "style" {
+			return e, true
+		}
+	}
+	switch key {
+	case "class":
+		return Classes(v).String(), true
+	case "style":
+		return Styles(v).String(), true
+	}
+	return expr.Eval(v), true
+}
+
+// kebabCase converts a go field name like BackgroundColor to a css property
+// name background-color.
+func kebabCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isZero(v reflect.Value) bool {
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
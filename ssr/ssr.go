@@ -0,0 +1,209 @@
+// Package ssr renders nodes to html outside the browser. It is used to
+// prerender pages so they show before the wasm module is loaded.
+//
+// Components are rendered by calling their Render method with the props of
+// the node, values of component nodes are copied to a new pointer first so
+// methods with pointer receivers work.
+package ssr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/gernest/greact/node"
+)
+
+// voidElements are elements without closing tags.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawElements are elements whose text is written as it is.
+var rawElements = map[string]bool{
+	"script": true,
+	"style":  true,
+}
+
+// Render writes the html of n to w.
+func Render(ctx context.Context, w io.Writer, n *node.Node) error {
+	r := &renderer{ctx: ctx, w: w}
+	r.node(n, false)
+	return r.err
+}
+
+// RenderString returns the html of n.
+func RenderString(ctx context.Context, n *node.Node) (string, error) {
+	var buf bytes.Buffer
+	if err := Render(ctx, &buf, n); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderComponent returns the html of c rendered with props.
+func RenderComponent(ctx context.Context, c node.Component, props node.Props) (string, error) {
+	return RenderString(ctx, c.Render(ctx, props, nil))
+}
+
+// WritePages renders routes, which map paths to components, and writes a json
+// object that maps the paths to html.
+func WritePages(w io.Writer, routes map[string]node.Component) error {
+	paths := make([]string, 0, len(routes))
+	for p := range routes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	pages := make(map[string]string)
+	for _, p := range paths {
+		s, err := RenderComponent(context.Background(), routes[p], nil)
+		if err != nil {
+			return fmt.Errorf("%s: %v", p, err)
+		}
+		pages[p] = s
+	}
+	e := json.NewEncoder(w)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+	return e.Encode(pages)
+}
+
+type renderer struct {
+	ctx context.Context
+	w   io.Writer
+	err error
+}
+
+func (r *renderer) write(s string) {
+	if r.err != nil {
+		return
+	}
+	_, r.err = io.WriteString(r.w, s)
+}
+
+func (r *renderer) node(n *node.Node, raw bool) {
+	if n == nil || r.err != nil {
+		return
+	}
+	typ, ok := n.Type.(node.NodeType)
+	if !ok {
+		r.component(n)
+		return
+	}
+	switch typ {
+	case node.TextNode:
+		if raw {
+			r.write(n.Data)
+		} else {
+			r.write(html.EscapeString(n.Data))
+		}
+	case node.CommentNode:
+		r.write("<!--" + n.Data + "-->")
+	case node.DoctypeNode:
+		r.write("<!DOCTYPE " + n.Data + ">")
+	case node.DocumentNode:
+		r.children(n, false)
+	case node.ElementNode:
+		r.element(n)
+	default:
+		r.err = fmt.Errorf("ssr: can't render %v node %q", typ, n.Data)
+	}
+}
+
+func (r *renderer) element(n *node.Node) {
+	r.write("<" + n.Data)
+	for _, a := range n.Attr {
+		if a.Key == "children" || !renderable(a.Val) {
+			continue
+		}
+		s, ok := node.AttrString(a.Key, a.Val)
+		if !ok {
+			continue
+		}
+		key := a.Key
+		if a.Namespace != "" {
+			key = a.Namespace + ":" + key
+		}
+		if s == "" {
+			if _, isBool := a.Val.(bool); isBool {
+				r.write(" " + key)
+				continue
+			}
+		}
+		r.write(" " + key + `="` + html.EscapeString(s) + `"`)
+	}
+	r.write(">")
+	if voidElements[n.Data] && n.Namespace == "" {
+		return
+	}
+	r.children(n, rawElements[n.Data])
+	r.write("</" + n.Data + ">")
+}
+
+// renderable returns false for values that can't be attributes in html, like
+// event handlers.
+func renderable(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Func, reflect.Chan:
+		return false
+	}
+	return true
+}
+
+func (r *renderer) children(n *node.Node, raw bool) {
+	for _, c := range children(n) {
+		r.node(c, raw)
+	}
+}
+
+func children(n *node.Node) []*node.Node {
+	if len(n.Children) > 0 {
+		return n.Children
+	}
+	for _, a := range n.Attr {
+		if a.Key == "children" {
+			c, _ := a.Val.([]*node.Node)
+			return c
+		}
+	}
+	return nil
+}
+
+// component renders the component node n.
+func (r *renderer) component(n *node.Node) {
+	c, ok := newComponent(n.Type)
+	if !ok {
+		r.err = fmt.Errorf("ssr: <%s>: %T is not a component", n.Data, n.Type)
+		return
+	}
+	props := make(node.Props)
+	for _, a := range n.Attr {
+		props[a.Key] = a
+	}
+	r.node(c.Render(r.ctx, props, nil), false)
+}
+
+// newComponent returns a component for the value typ of a component node.
+func newComponent(typ interface{}) (node.Component, bool) {
+	if typ == nil {
+		return nil, false
+	}
+	v := reflect.ValueOf(typ)
+	if v.Kind() != reflect.Ptr {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		v = p
+	}
+	c, ok := v.Interface().(node.Component)
+	return c, ok
+}
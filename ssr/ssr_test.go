@@ -0,0 +1,107 @@
+package ssr
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/gernest/greact/node"
+	"github.com/gernest/greact/tmpl"
+)
+
+type greeting struct{}
+
+func (g *greeting) Render(ctx context.Context, props node.Props, state node.State) *node.Node {
+	return node.New(node.ElementNode, "", "b", nil,
+		node.New(node.TextNode, "", "Hi "+props["name"].Val.(string), nil),
+	)
+}
+
+type page struct {
+	Title string
+}
+
+var pageTemplate = tmpl.Must(tmpl.New(`<main class:wide={true}>
+	<h1 title="{p.Title}">{p.Title}</h1>
+	<greeting name="gopher"></greeting>
+	<input type="text" disabled={true} required={false}>
+	<button onclick={click}>go</button>
+	<p style={style}>a &lt; b</p>
+</main>`)).Component("greeting", greeting{})
+
+func (p *page) Render(ctx context.Context, props node.Props, state node.State) *node.Node {
+	n, err := pageTemplate.Execute(tmpl.Scope{
+		"p":     p,
+		"click": func() {},
+		"style": map[string]string{"color": "red"},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func TestRenderComponent(t *testing.T) {
+	got, err := RenderComponent(context.Background(), &page{Title: `"Tom" & Jerry`}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `<main class="wide">` +
+		`<h1 title="&#34;Tom&#34; &amp; Jerry">&#34;Tom&#34; &amp; Jerry</h1>` +
+		`<b>Hi gopher</b>` +
+		`<input type="text" disabled>` +
+		`<button>go</button>` +
+		`<p style="color: red;">a &lt; b</p>` +
+		`</main>`
+	if got != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, got)
+	}
+}
+
+func TestWritePages(t *testing.T) {
+	var buf bytes.Buffer
+	err := WritePages(&buf, map[string]node.Component{
+		"/":      &greetingPage{},
+		"/about": &page{Title: "About"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{
+  "/": "<b>Hi home</b>",
+  "/about": "<main class=\"wide\"><h1 title=\"About\">About</h1><b>Hi gopher</b><input type=\"text\" disabled><button>go</button><p style=\"color: red;\">a &lt; b</p></main>"
+}
+`
+	if buf.String() != expect {
+		t.Errorf("expected:\n%s\ngot:\n%s", expect, buf.String())
+	}
+}
+
+type greetingPage struct{}
+
+func (g *greetingPage) Render(ctx context.Context, props node.Props, state node.State) *node.Node {
+	return node.NewComponent(greeting{}, "greeting", []node.Attribute{node.Attr("", "name", "home")})
+}
+
+func TestRenderRaw(t *testing.T) {
+	n := node.New(node.ElementNode, "", "div", nil,
+		node.New(node.ElementNode, "", "style", nil, node.New(node.TextNode, "", "p > a {}", nil)),
+		node.New(node.ElementNode, "", "br", nil),
+		node.New(node.CommentNode, "", " note ", nil),
+	)
+	got, err := RenderString(context.Background(), n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `<div><style>p > a {}</style><br><!-- note --></div>`
+	if got != expect {
+		t.Errorf("expected %s got %s", expect, got)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	n := &node.Node{Type: struct{}{}, Data: "thing"}
+	if _, err := RenderString(context.Background(), n); err == nil {
+		t.Error("expected an error for a node whose type is not a component")
+	}
+}
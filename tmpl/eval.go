@@ -0,0 +1,487 @@
+package tmpl
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+)
+
+// Scope maps names used by expressions to their values.
+type Scope map[string]interface{}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Eval evaluates the go expression src with names resolved from scope.
+//
+// Only a subset of go is supported: literals, names, field and method
+// selectors, calls, indexing of maps, slices, arrays and strings, and unary and
+// binary operators on booleans, numbers and strings. Calls to functions whose
+// last result is an error fail when the error is not nil.
+func Eval(src string, scope Scope) (interface{}, error) {
+	e, err := parser.ParseExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	v, err := eval(e, scope)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", src, err)
+	}
+	if !v.IsValid() {
+		return nil, nil
+	}
+	return v.Interface(), nil
+}
+
+// eval returns the value of e, the zero Value stands for nil.
+func eval(e ast.Expr, scope Scope) (reflect.Value, error) {
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return eval(e.X, scope)
+	case *ast.BasicLit:
+		return literal(e)
+	case *ast.Ident:
+		if v, ok := scope[e.Name]; ok {
+			return reflect.ValueOf(v), nil
+		}
+		switch e.Name {
+		case "true":
+			return reflect.ValueOf(true), nil
+		case "false":
+			return reflect.ValueOf(false), nil
+		case "nil":
+			return reflect.Value{}, nil
+		}
+		return reflect.Value{}, fmt.Errorf("undefined: %s", e.Name)
+	case *ast.SelectorExpr:
+		x, err := eval(e.X, scope)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return selector(x, e.Sel.Name)
+	case *ast.IndexExpr:
+		x, err := eval(e.X, scope)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		i, err := eval(e.Index, scope)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return index(x, i)
+	case *ast.CallExpr:
+		fn, err := eval(e.Fun, scope)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		var args []reflect.Value
+		for _, a := range e.Args {
+			v, err := eval(a, scope)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			args = append(args, v)
+		}
+		return call(fn, args, e.Ellipsis.IsValid())
+	case *ast.UnaryExpr:
+		x, err := eval(e.X, scope)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return unary(e.Op, x)
+	case *ast.BinaryExpr:
+		x, err := eval(e.X, scope)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		// && and || only evaluate the right operand when needed.
+		if e.Op == token.LAND || e.Op == token.LOR {
+			b, ok := boolean(x)
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("operator %s not defined on %s", e.Op, typeName(x))
+			}
+			if (e.Op == token.LAND) != b {
+				return reflect.ValueOf(b), nil
+			}
+			y, err := eval(e.Y, scope)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			b, ok = boolean(y)
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("operator %s not defined on %s", e.Op, typeName(y))
+			}
+			return reflect.ValueOf(b), nil
+		}
+		y, err := eval(e.Y, scope)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return binary(e.Op, x, y)
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported expression %T", e)
+}
+
+func literal(e *ast.BasicLit) (reflect.Value, error) {
+	switch e.Kind {
+	case token.INT:
+		i, err := strconv.ParseInt(e.Value, 0, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(int(i)), nil
+	case token.FLOAT:
+		f, err := strconv.ParseFloat(e.Value, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(f), nil
+	case token.STRING:
+		s, err := strconv.Unquote(e.Value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(s), nil
+	case token.CHAR:
+		v := constant.MakeFromLiteral(e.Value, e.Kind, 0)
+		r, ok := constant.Int64Val(v)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("invalid rune literal %s", e.Value)
+		}
+		return reflect.ValueOf(rune(r)), nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported literal %s", e.Value)
+}
+
+// selector returns the field or method name of x. Pointers are followed to
+// find fields and methods declared on their element types.
+func selector(x reflect.Value, name string) (reflect.Value, error) {
+	if !x.IsValid() {
+		return reflect.Value{}, fmt.Errorf("selector %s of nil", name)
+	}
+	if x.Kind() == reflect.Interface && x.IsNil() {
+		return reflect.Value{}, fmt.Errorf("selector %s of nil interface %s", name, typeName(x))
+	}
+	if m := x.MethodByName(name); m.IsValid() {
+		return m, nil
+	}
+	v := x
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, fmt.Errorf("selector %s of nil %s", name, typeName(x))
+		}
+		v = v.Elem()
+		if m := v.MethodByName(name); m.IsValid() {
+			return m, nil
+		}
+	}
+	if v.Kind() == reflect.Struct {
+		if f, ok := v.Type().FieldByName(name); ok && f.PkgPath == "" {
+			return v.FieldByIndex(f.Index), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("%s has no field or method %s", typeName(x), name)
+}
+
+func index(x, i reflect.Value) (reflect.Value, error) {
+	x = indirect(x)
+	if !x.IsValid() {
+		return reflect.Value{}, errors.New("index of nil")
+	}
+	switch x.Kind() {
+	case reflect.Map:
+		k, err := convert(i, x.Type().Key())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v := x.MapIndex(k)
+		if !v.IsValid() {
+			return reflect.Zero(x.Type().Elem()), nil
+		}
+		return v, nil
+	case reflect.Slice, reflect.Array, reflect.String:
+		n, ok := integer(i)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("invalid index of type %s", typeName(i))
+		}
+		if n < 0 || n >= int64(x.Len()) {
+			return reflect.Value{}, fmt.Errorf("index %d out of range [0:%d]", n, x.Len())
+		}
+		return x.Index(int(n)), nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot index %s", typeName(x))
+}
+
+func call(fn reflect.Value, args []reflect.Value, ellipsis bool) (reflect.Value, error) {
+	fn = indirect(fn)
+	if !fn.IsValid() || fn.Kind() != reflect.Func {
+		return reflect.Value{}, fmt.Errorf("cannot call non-function %s", typeName(fn))
+	}
+	typ := fn.Type()
+	n := typ.NumIn()
+	if typ.IsVariadic() && !ellipsis {
+		if len(args) < n-1 {
+			return reflect.Value{}, fmt.Errorf("not enough arguments in call to %s", typ)
+		}
+	} else if len(args) != n {
+		return reflect.Value{}, fmt.Errorf("wrong number of arguments in call to %s", typ)
+	}
+	in := make([]reflect.Value, len(args))
+	for i, a := range args {
+		var t reflect.Type
+		switch {
+		case typ.IsVariadic() && i >= n-1 && !ellipsis:
+			t = typ.In(n - 1).Elem()
+		default:
+			t = typ.In(i)
+		}
+		v, err := convert(a, t)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		in[i] = v
+	}
+	var out []reflect.Value
+	if ellipsis {
+		out = fn.CallSlice(in)
+	} else {
+		out = fn.Call(in)
+	}
+	if len(out) > 0 && typ.Out(len(out)-1) == errorType {
+		if err := out[len(out)-1]; !err.IsNil() {
+			return reflect.Value{}, err.Interface().(error)
+		}
+		out = out[:len(out)-1]
+	}
+	if len(out) == 0 {
+		return reflect.Value{}, nil
+	}
+	return out[0], nil
+}
+
+// convert returns v as a value of type t.
+func convert(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if v.IsValid() && v.Kind() == reflect.Interface && t.Kind() != reflect.Interface {
+		v = indirect(v)
+	}
+	if !v.IsValid() {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("cannot use nil as %s", t)
+	}
+	if v.Type().AssignableTo(t) {
+		return v, nil
+	}
+	if numeric(v) && isNumericKind(t.Kind()) {
+		return v.Convert(t), nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot use %s as %s", v.Type(), t)
+}
+
+func unary(op token.Token, x reflect.Value) (reflect.Value, error) {
+	switch op {
+	case token.NOT:
+		if b, ok := boolean(x); ok {
+			return reflect.ValueOf(!b), nil
+		}
+	case token.ADD:
+		if numeric(x) {
+			return x, nil
+		}
+	case token.SUB:
+		x = indirect(x)
+		switch {
+		case isInt(x):
+			return reflect.ValueOf(-x.Int()).Convert(x.Type()), nil
+		case isFloat(x):
+			return reflect.ValueOf(-x.Float()).Convert(x.Type()), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("operator %s not defined on %s", op, typeName(x))
+}
+
+func binary(op token.Token, x, y reflect.Value) (reflect.Value, error) {
+	x, y = indirect(x), indirect(y)
+	switch op {
+	case token.EQL, token.NEQ:
+		eq := equal(x, y)
+		return reflect.ValueOf(eq == (op == token.EQL)), nil
+	}
+	switch {
+	case x.IsValid() && y.IsValid() && x.Kind() == reflect.String && y.Kind() == reflect.String:
+		a, b := x.String(), y.String()
+		switch op {
+		case token.ADD:
+			return reflect.ValueOf(a + b).Convert(x.Type()), nil
+		case token.LSS:
+			return reflect.ValueOf(a < b), nil
+		case token.LEQ:
+			return reflect.ValueOf(a <= b), nil
+		case token.GTR:
+			return reflect.ValueOf(a > b), nil
+		case token.GEQ:
+			return reflect.ValueOf(a >= b), nil
+		}
+	case numeric(x) && numeric(y):
+		return arithmetic(op, x, y)
+	}
+	return reflect.Value{}, fmt.Errorf("operator %s not defined on %s and %s", op, typeName(x), typeName(y))
+}
+
+// arithmetic applies op to numbers x and y. The result has the type of x
+// unless x is a plain int like integer literals and y is not, this lets
+// expressions like 1 + c.Count keep the type of Count.
+func arithmetic(op token.Token, x, y reflect.Value) (reflect.Value, error) {
+	typ := x.Type()
+	if typ.Kind() == reflect.Int && typ.PkgPath() == "" || isFloat(y) && !isFloat(x) {
+		typ = y.Type()
+	}
+	if isFloat(x) || isFloat(y) {
+		a, b := float(x), float(y)
+		var r float64
+		switch op {
+		case token.ADD:
+			r = a + b
+		case token.SUB:
+			r = a - b
+		case token.MUL:
+			r = a * b
+		case token.QUO:
+			r = a / b
+		default:
+			return compare(op, a < b, a == b)
+		}
+		return reflect.ValueOf(r).Convert(typ), nil
+	}
+	a, _ := integer(x)
+	b, _ := integer(y)
+	var r int64
+	switch op {
+	case token.ADD:
+		r = a + b
+	case token.SUB:
+		r = a - b
+	case token.MUL:
+		r = a * b
+	case token.QUO, token.REM:
+		if b == 0 {
+			return reflect.Value{}, errors.New("integer divide by zero")
+		}
+		if op == token.QUO {
+			r = a / b
+		} else {
+			r = a % b
+		}
+	default:
+		return compare(op, a < b, a == b)
+	}
+	return reflect.ValueOf(r).Convert(typ), nil
+}
+
+func compare(op token.Token, less, eq bool) (reflect.Value, error) {
+	switch op {
+	case token.LSS:
+		return reflect.ValueOf(less), nil
+	case token.LEQ:
+		return reflect.ValueOf(less || eq), nil
+	case token.GTR:
+		return reflect.ValueOf(!less && !eq), nil
+	case token.GEQ:
+		return reflect.ValueOf(!less), nil
+	}
+	return reflect.Value{}, fmt.Errorf("operator %s not supported", op)
+}
+
+func equal(x, y reflect.Value) bool {
+	switch {
+	case !x.IsValid() || !y.IsValid():
+		return isNil(x) && isNil(y)
+	case numeric(x) && numeric(y):
+		if isFloat(x) || isFloat(y) {
+			return float(x) == float(y)
+		}
+		a, _ := integer(x)
+		b, _ := integer(y)
+		return a == b
+	}
+	return reflect.DeepEqual(x.Interface(), y.Interface())
+}
+
+func isNil(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
+
+// indirect returns the value held by interface values.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func boolean(v reflect.Value) (bool, bool) {
+	v = indirect(v)
+	if !v.IsValid() || v.Kind() != reflect.Bool {
+		return false, false
+	}
+	return v.Bool(), true
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	return reflect.Int <= k && k <= reflect.Float64
+}
+
+func numeric(v reflect.Value) bool {
+	v = indirect(v)
+	return v.IsValid() && isNumericKind(v.Kind())
+}
+
+func isInt(v reflect.Value) bool {
+	return v.IsValid() && reflect.Int <= v.Kind() && v.Kind() <= reflect.Int64
+}
+
+func isFloat(v reflect.Value) bool {
+	return v.IsValid() && (v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64)
+}
+
+func integer(v reflect.Value) (int64, bool) {
+	v = indirect(v)
+	switch {
+	case isInt(v):
+		return v.Int(), true
+	case v.IsValid() && reflect.Uint <= v.Kind() && v.Kind() <= reflect.Uintptr:
+		return int64(v.Uint()), true
+	}
+	return 0, false
+}
+
+func float(v reflect.Value) float64 {
+	if isFloat(v) {
+		return v.Float()
+	}
+	i, _ := integer(v)
+	return float64(i)
+}
+
+func typeName(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	return v.Type().String()
+}
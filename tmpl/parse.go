@@ -0,0 +1,176 @@
+// Package tmpl parses greact templates and renders them at runtime.
+//
+// Templates are html with go expressions in braces, {expr} in text and
+// attribute values and {...expr} for spread attributes. The generator compiles
+// them to Render methods while Template evaluates them when they are executed.
+package tmpl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/gernest/greact/expr"
+	"github.com/gernest/greact/node"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// spreadPrefix is the prefix of attributes that stand for spread attributes
+// while the template is parsed as html.
+const spreadPrefix = "greact-spread-"
+
+// ToNode recursively transform n to a *Node.
+func ToNode(n *html.Node) *node.Node {
+	return toNode(n, nil)
+}
+
+func toNode(n *html.Node, spreads []string) *node.Node {
+	nd := &node.Node{
+		Type:      node.NodeType(uint32(n.Type)),
+		Data:      n.Data,
+		Namespace: n.Namespace,
+	}
+	for _, v := range n.Attr {
+		if v.Key == "" {
+			continue
+		}
+		if strings.HasPrefix(v.Key, spreadPrefix) {
+			i, err := strconv.Atoi(strings.TrimPrefix(v.Key, spreadPrefix))
+			if err == nil && i < len(spreads) {
+				nd.Attr = append(nd.Attr, node.Attribute{
+					Key: node.SpreadKey,
+					Val: "{" + spreads[i] + "}",
+				})
+				continue
+			}
+		}
+		nd.Attr = append(nd.Attr, node.Attribute{
+			Namespace: v.Namespace,
+			Key:       v.Key,
+			Val:       v.Val,
+		})
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode && strings.TrimSpace(c.Data) == "" {
+			continue
+		}
+		nd.Children = append(nd.Children, toNode(c, spreads))
+	}
+	return nd
+}
+
+// preprocess replaces spread attributes {...expr} in tags of src with
+// placeholder attributes and quotes unquoted expression values of attributes.
+// The html parser would otherwise lower case the expressions and split them on
+// white space. The returned slice holds the spread expressions, the index of an
+// expression is the suffix of its placeholder.
+func preprocess(src string) (string, []string, error) {
+	var buf bytes.Buffer
+	var spreads []string
+	inTag := false
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case !inTag:
+			if c == '<' && i+1 < len(src) && isLetter(src[i+1]) {
+				inTag = true
+			}
+		case c == '>':
+			inTag = false
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], c)
+			if end == -1 {
+				buf.WriteString(src[i:])
+				return buf.String(), spreads, nil
+			}
+			buf.WriteString(src[i : i+end+2])
+			i += end + 2
+			continue
+		case c == '{':
+			text, size, err := expr.ScanExpression(src[i:], '{', '}')
+			if err != nil {
+				return "", nil, err
+			}
+			switch {
+			case strings.HasPrefix(text, "..."):
+				fmt.Fprintf(&buf, " %s%d ", spreadPrefix, len(spreads))
+				spreads = append(spreads, strings.TrimSpace(strings.TrimPrefix(text, "...")))
+			case i > 0 && src[i-1] == '=':
+				// entities are unescaped by the html parser.
+				buf.WriteString(`"` + html.EscapeString(src[i:i+size]) + `"`)
+			default:
+				buf.WriteString(src[i : i+size])
+			}
+			i += size
+			continue
+		}
+		buf.WriteByte(c)
+		i++
+	}
+	return buf.String(), spreads, nil
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// Parse parses src as html component definition and returns their *Node
+// representation. r must be reading from a subset of xml/html document that is
+// going to processed and compiled to *Node.
+//
+// Tags can have spread attributes {...expr} where expr evaluates to a list of
+// attributes to add to the element. They are represented by attributes with
+// node.SpreadKey as the key.
+func Parse(r io.Reader) (*node.Node, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	src, spreads, err := preprocess(string(b))
+	if err != nil {
+		return nil, err
+	}
+	base := root()
+	n, err := html.ParseFragment(strings.NewReader(src), base)
+	if err != nil {
+		return nil, err
+	}
+	var rst []*node.Node
+	for _, v := range n {
+		nd := toNode(v, spreads)
+		if nd.Type == node.TextNode && strings.TrimSpace(nd.Data) == "" {
+			continue
+		}
+		rst = append(rst, nd)
+	}
+	container := &node.Node{
+		Type: node.ElementNode,
+		Data: "div",
+	}
+	switch len(rst) {
+	case 0:
+		return container, nil
+	case 1:
+		return rst[0], nil
+	default:
+		container.Children = rst
+		return container, nil
+	}
+}
+
+func root() *html.Node {
+	return &html.Node{
+		DataAtom: atom.Div,
+		Type:     html.ElementNode,
+		Data:     "div",
+	}
+}
+
+// ParseString helper that wraps s to io.Reader.
+func ParseString(s string) (*node.Node, error) {
+	return Parse(strings.NewReader(s))
+}
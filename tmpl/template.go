@@ -0,0 +1,208 @@
+package tmpl
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"strings"
+
+	"github.com/gernest/greact/elements"
+	"github.com/gernest/greact/expr"
+	"github.com/gernest/greact/node"
+)
+
+// Template is a parsed template that is rendered at runtime, expressions are
+// evaluated with Eval instead of being compiled by the generator. This is
+// slower than generated Render methods but needs no code generation, which is
+// useful for prototyping and for templates that are only known at runtime.
+//
+//	t, err := tmpl.New(`<p class:active={c.Active}>Hello {props["name"].Val}</p>`)
+//	if err != nil {
+//		return err
+//	}
+//	n, err := t.Execute(tmpl.Scope{"c": c, "props": props})
+type Template struct {
+	root       *node.Node
+	exprs      map[string]ast.Expr
+	components map[string]interface{}
+}
+
+// New parses src and the expressions it has.
+func New(src string) (*Template, error) {
+	root, err := ParseString(src)
+	if err != nil {
+		return nil, err
+	}
+	t := &Template{
+		root:       root,
+		exprs:      make(map[string]ast.Expr),
+		components: make(map[string]interface{}),
+	}
+	if err := t.parseExprs(root); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Must is a helper that wraps a call to New and panics if the error is not
+// nil.
+func Must(t *Template, err error) *Template {
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Component makes tags named name render the component v, v is used as the
+// type of the created nodes like in generated code. Tag names are case
+// insensitive.
+func (t *Template) Component(name string, v interface{}) *Template {
+	t.components[strings.ToLower(name)] = v
+	return t
+}
+
+func (t *Template) parseExprs(nd *node.Node) error {
+	add := func(src string) error {
+		parts, err := expr.ExtractExpressions(src, '{', '}')
+		if err != nil {
+			return err
+		}
+		for _, p := range parts {
+			if p.Plain {
+				continue
+			}
+			if _, ok := t.exprs[p.Text]; ok {
+				continue
+			}
+			e, err := parser.ParseExpr(p.Text)
+			if err != nil {
+				return fmt.Errorf("{%s}: %v", p.Text, err)
+			}
+			t.exprs[p.Text] = e
+		}
+		return nil
+	}
+	if nd.Type == node.TextNode {
+		if err := add(nd.Data); err != nil {
+			return err
+		}
+	}
+	for _, a := range nd.Attr {
+		if s, ok := a.Val.(string); ok {
+			if err := add(s); err != nil {
+				return err
+			}
+		}
+	}
+	for _, c := range nd.Children {
+		if err := t.parseExprs(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute renders the template with names in expressions resolved from scope.
+func (t *Template) Execute(scope Scope) (*node.Node, error) {
+	return t.execute(t.root, scope)
+}
+
+func (t *Template) execute(nd *node.Node, scope Scope) (*node.Node, error) {
+	if nd.Type == node.TextNode {
+		s, err := t.text(nd.Data, scope)
+		if err != nil {
+			return nil, err
+		}
+		return node.New(node.TextNode, "", s, nil), nil
+	}
+	var component interface{}
+	if nd.Type == node.ElementNode && nd.Namespace == "" && !elements.Valid(nd.Data) {
+		c, ok := t.components[nd.Data]
+		if !ok {
+			return nil, fmt.Errorf("<%s>: unknown component", nd.Data)
+		}
+		if !node.IsComponent(c) {
+			return nil, fmt.Errorf("<%s>: %T is not a component", nd.Data, c)
+		}
+		component = c
+	}
+	var attrs []node.Attribute
+	for _, a := range nd.Attr {
+		v, err := t.value(a.Val, scope)
+		if err != nil {
+			return nil, fmt.Errorf("<%s %s>: %v", nd.Data, a.Key, err)
+		}
+		attrs = append(attrs, node.Attr(a.Namespace, a.Key, v))
+	}
+	var children []*node.Node
+	for _, c := range nd.Children {
+		n, err := t.execute(c, scope)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, n)
+	}
+	if component != nil {
+		return node.NewComponent(component, nd.Data, attrs, children...), nil
+	}
+	return node.New(nd.Type.(node.NodeType), nd.Namespace, nd.Data, attrs, children...), nil
+}
+
+// text returns the text of src with values of its expressions, this matches
+// what generated code does with expr.Eval.
+func (t *Template) text(src string, scope Scope) (string, error) {
+	parts, err := expr.ExtractExpressions(src, '{', '}')
+	if err != nil {
+		return "", err
+	}
+	var args []interface{}
+	for _, p := range parts {
+		if p.Plain {
+			args = append(args, p.Text)
+			continue
+		}
+		v, err := t.eval(p.Text, scope)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, v)
+	}
+	return expr.Eval(args...), nil
+}
+
+// value returns the value of an attribute. A single expression gives its value
+// as it is so attributes can have values of any type.
+func (t *Template) value(v interface{}, scope Scope) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, nil
+	}
+	parts, err := expr.ExtractExpressions(s, '{', '}')
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(parts) == 0:
+		return "", nil
+	case len(parts) == 1 && parts[0].Plain:
+		return parts[0].Text, nil
+	case len(parts) == 1:
+		return t.eval(parts[0].Text, scope)
+	}
+	return t.text(s, scope)
+}
+
+func (t *Template) eval(src string, scope Scope) (interface{}, error) {
+	e, ok := t.exprs[src]
+	if !ok {
+		return Eval(src, scope)
+	}
+	v, err := eval(e, scope)
+	if err != nil {
+		return nil, fmt.Errorf("{%s}: %v", src, err)
+	}
+	if !v.IsValid() {
+		return nil, nil
+	}
+	return v.Interface(), nil
+}
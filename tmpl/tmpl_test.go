@@ -0,0 +1,160 @@
+package tmpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gernest/greact/node"
+)
+
+type user struct {
+	Name   string
+	Age    int
+	Admin  bool
+	Tags   []string
+	Friend *user
+}
+
+func (u user) Greet(greeting string) string {
+	return greeting + ", " + u.Name
+}
+
+func (u *user) Older(years int) int {
+	return u.Age + years
+}
+
+func (u user) Fail() (string, error) {
+	return "", errors.New("failed")
+}
+
+func TestEval(t *testing.T) {
+	u := &user{Name: "gernest", Age: 30, Tags: []string{"go", "wasm"}, Friend: &user{Name: "ana"}}
+	scope := Scope{
+		"u":     u,
+		"props": node.Props{"title": node.Attr("", "title", "hello")},
+		"count": int64(2),
+		"m":     map[string]fmt.Stringer{"k": nil},
+		"join":  strings.Join,
+		"sum": func(n ...int) int {
+			s := 0
+			for _, v := range n {
+				s += v
+			}
+			return s
+		},
+	}
+	sample := []struct {
+		src    string
+		expect interface{}
+	}{
+		{`u.Name`, "gernest"},
+		{`u.Friend.Name`, "ana"},
+		{`u.Greet("hello")`, "hello, gernest"},
+		{`u.Older(2)`, 32},
+		{`u.Tags[1]`, "wasm"},
+		{`props["title"].Val`, "hello"},
+		{`props["missing"].Val`, nil},
+		{`u.Age + 1`, 31},
+		{`1 + count`, int64(3)},
+		{`u.Age / 4.0`, 7.5},
+		{`u.Age % 7`, 2},
+		{`-u.Age`, -30},
+		{`u.Age >= 18 && !u.Admin`, true},
+		{`u.Admin || u.Name == "gernest"`, true},
+		{`u.Name + "!"`, "gernest!"},
+		{`u.Friend != nil`, true},
+		{`u.Name < "z"`, true},
+		{`join(u.Tags, "-")`, "go-wasm"},
+		{`sum(1, 2, 3)`, 6},
+		{`sum()`, 0},
+		{`'a'`, 'a'},
+		{`(2 + 3) * 2`, 10},
+	}
+	for _, v := range sample {
+		got, err := Eval(v.src, scope)
+		if err != nil {
+			t.Errorf("%s: %v", v.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, v.expect) {
+			t.Errorf("%s: expected %#v got %#v", v.src, v.expect, got)
+		}
+	}
+	errs := []struct {
+		src, expect string
+	}{
+		{`missing`, "missing: undefined: missing"},
+		{`u.Nmae`, "u.Nmae: *tmpl.user has no field or method Nmae"},
+		{`u.Fail()`, "u.Fail(): failed"},
+		{`u.Name - 1`, "u.Name - 1: operator - not defined on string and int"},
+		{`u.Tags[5]`, "u.Tags[5]: index 5 out of range [0:2]"},
+		{`u.Age / 0`, "u.Age / 0: integer divide by zero"},
+		{`u.Greet(1)`, "u.Greet(1): cannot use int as string"},
+		{`m["k"].String`, `m["k"].String: selector String of nil interface fmt.Stringer`},
+		{`m["missing"].String()`, `m["missing"].String(): selector String of nil interface fmt.Stringer`},
+	}
+	for _, v := range errs {
+		_, err := Eval(v.src, scope)
+		if err == nil || err.Error() != v.expect {
+			t.Errorf("%s: expected error %q got %v", v.src, v.expect, err)
+		}
+	}
+}
+
+type card struct{}
+
+func (c *card) Render(ctx context.Context, props node.Props, state node.State) *node.Node {
+	return nil
+}
+
+func TestTemplate(t *testing.T) {
+	tpl := Must(New(`<div class="user" class:admin={u.Admin} {...attrs}>
+	<h1 title={u.Name}>{u.Greet("Hello")}</h1>
+	<card count={len(u.Tags)} tags={u.Tags}></card>
+</div>`)).Component("card", card{})
+	u := &user{Name: "gernest", Admin: true, Tags: []string{"go"}}
+	n, err := tpl.Execute(Scope{
+		"u":     u,
+		"attrs": map[string]interface{}{"id": "main"},
+		"len":   func(s []string) int { return len(s) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := node.New(3, "", "div", node.Attrs(
+		node.Attr("", "class", "user"),
+		node.Attr("", "class:admin", true),
+		node.Attr("", node.SpreadKey, map[string]interface{}{"id": "main"}),
+	),
+		node.New(3, "", "h1", node.Attrs(node.Attr("", "title", "gernest")),
+			node.New(1, "", "Hello, gernest", nil),
+		),
+		node.NewComponent(card{}, "card", node.Attrs(
+			node.Attr("", "count", 1),
+			node.Attr("", "tags", []string{"go"}),
+		)),
+	)
+	if !reflect.DeepEqual(n, expect) {
+		t.Errorf("expected %#v got %#v", expect, n)
+	}
+
+	if _, err := New(`<p>{x :=}</p>`); err == nil {
+		t.Error("expected an error for invalid expressions")
+	}
+	_, err = Must(New(`<unknown></unknown>`)).Execute(nil)
+	if err == nil || err.Error() != "<unknown>: unknown component" {
+		t.Errorf("expected unknown component error got %v", err)
+	}
+	_, err = Must(New(`<user></user>`)).Component("user", user{}).Execute(nil)
+	if err == nil || err.Error() != "<user>: tmpl.user is not a component" {
+		t.Errorf("expected not a component error got %v", err)
+	}
+	_, err = Must(New(`<p>{u.Nmae}</p>`)).Execute(Scope{"u": u})
+	if err == nil || err.Error() != "{u.Nmae}: *tmpl.user has no field or method Nmae" {
+		t.Errorf("expected evaluation error got %v", err)
+	}
+}